@@ -0,0 +1,13 @@
+# List every alias of the active multipass context
+data "multipass_aliases" "all" {}
+
+# List the aliases running in one instance
+data "multipass_aliases" "k8s" {
+  instance = "k8s"
+}
+
+output "k8s_alias_commands" {
+  value = {
+    for alias in data.multipass_aliases.k8s.aliases : alias.name => alias.command
+  }
+}
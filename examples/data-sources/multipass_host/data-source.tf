@@ -0,0 +1,12 @@
+# Size an instance to half of the memory currently available on the host
+data "multipass_host" "current" {}
+
+resource "multipass_instance" "sized" {
+  name   = "sized-vm"
+  cpu    = tostring(max(1, floor(data.multipass_host.current.cpu_count / 2)))
+  memory = "${floor(data.multipass_host.current.memory_available / 2 / 1048576)}M"
+}
+
+output "host_disk_available" {
+  value = data.multipass_host.current.disk_available
+}
@@ -7,11 +7,46 @@ data "multipass_instance" "example" {
 data "multipass_instance" "all" {
 }
 
+# List running web instances with an IP address
+data "multipass_instance" "web" {
+  filter {
+    name     = "web-*"
+    states   = ["Running"]
+    has_ipv4 = true
+  }
+
+  sort_by = "name"
+}
+
+# Look up an instance that may not exist yet
+data "multipass_instance" "maybe" {
+  name            = "build-cache"
+  fail_if_missing = false
+}
+
 # Use instance data
 output "instance_ip" {
   value = data.multipass_instance.example.instance.ipv4
 }
 
+output "instance_memory_used" {
+  value = data.multipass_instance.example.instance.memory.used
+}
+
+output "instance_mounts" {
+  value = {
+    for mount in data.multipass_instance.example.instance.mounts : mount.target => mount.source
+  }
+}
+
+output "web_instance_names" {
+  value = data.multipass_instance.web.names
+}
+
+output "build_cache_exists" {
+  value = data.multipass_instance.maybe.exists
+}
+
 output "all_instances" {
   value = data.multipass_instance.all.instances
 }
\ No newline at end of file
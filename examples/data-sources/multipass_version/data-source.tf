@@ -0,0 +1,10 @@
+# Inspect the installed multipass release
+data "multipass_version" "current" {}
+
+output "multipass_daemon_version" {
+  value = data.multipass_version.current.daemon_version
+}
+
+output "snapshots_supported" {
+  value = data.multipass_version.current.capabilities["snapshots"]
+}
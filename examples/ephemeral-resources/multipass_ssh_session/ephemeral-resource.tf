@@ -0,0 +1,23 @@
+resource "multipass_instance" "web" {
+  name = "web"
+}
+
+# Temporary SSH access that is revoked after the run and never stored in state
+ephemeral "multipass_ssh_session" "web" {
+  instance = multipass_instance.web.name
+}
+
+resource "terraform_data" "bootstrap" {
+  triggers_replace = [multipass_instance.web.id]
+
+  connection {
+    type        = "ssh"
+    host        = ephemeral.multipass_ssh_session.web.host
+    user        = ephemeral.multipass_ssh_session.web.user
+    private_key = ephemeral.multipass_ssh_session.web.private_key
+  }
+
+  provisioner "remote-exec" {
+    inline = ["cloud-init status --wait"]
+  }
+}
@@ -0,0 +1,10 @@
+# Combine a shared base configuration with per-instance additions
+output "web_cloud_init" {
+  value = provider::multipass::cloud_init_merge(
+    file("${path.module}/base.yaml"),
+    yamlencode({
+      packages = ["nginx"]
+      runcmd   = ["systemctl enable --now nginx"]
+    })
+  )
+}
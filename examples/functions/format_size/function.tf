@@ -0,0 +1,7 @@
+# Give an instance a quarter of the available host memory
+data "multipass_host" "current" {}
+
+resource "multipass_instance" "quarter" {
+  name   = "quarter"
+  memory = provider::multipass::format_size(floor(data.multipass_host.current.memory_available / 4 / 1048576) * 1048576)
+}
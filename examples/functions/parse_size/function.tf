@@ -0,0 +1,3 @@
+output "memory_bytes" {
+  value = provider::multipass::parse_size("1.5G") # 1610612736
+}
@@ -0,0 +1,7 @@
+data "multipass_instance" "web" {
+  name = "web"
+}
+
+output "lan_address" {
+  value = provider::multipass::primary_ipv4(data.multipass_instance.web.instance.ipv4, "bridged")
+}
@@ -0,0 +1,8 @@
+variable "instance_name" {
+  type = string
+
+  validation {
+    condition     = provider::multipass::valid_instance_name(var.instance_name)
+    error_message = "Instance names must start with a letter and contain only letters, digits and hyphens."
+  }
+}
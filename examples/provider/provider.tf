@@ -10,4 +10,19 @@ terraform {
 provider "multipass" {
   # Optional: specify path to multipass binary if not in PATH
   # binary_path = "/usr/local/bin/multipass"
+
+  # Optional: team-wide settings for instances that do not set them
+  # defaults {
+  #   image       = "22.04"
+  #   cpu         = "2"
+  #   memory      = "2G"
+  #   disk        = "10G"
+  #   name_prefix = "dev-"
+  # }
+
+  # Optional: fail the plan when new instances do not fit on the host
+  # capacity_check {
+  #   mode                 = "error"
+  #   cpu_overcommit_ratio = 2.0
+  # }
 }
\ No newline at end of file
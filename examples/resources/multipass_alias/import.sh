@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# Import an existing Multipass alias by name
+terraform import multipass_alias.kubectl kc
@@ -0,0 +1,21 @@
+resource "multipass_instance" "k8s" {
+  name   = "k8s"
+  cpu    = "2"
+  memory = "4G"
+  disk   = "20G"
+}
+
+# Run kubectl inside the VM from the host as "kc", in the mapped working directory
+resource "multipass_alias" "kubectl" {
+  name     = "kc"
+  instance = multipass_instance.k8s.name
+  command  = "kubectl"
+}
+
+# Always run helm from the home directory of the instance user
+resource "multipass_alias" "helm" {
+  name              = "helm"
+  instance          = multipass_instance.k8s.name
+  command           = "helm"
+  working_directory = "default"
+}
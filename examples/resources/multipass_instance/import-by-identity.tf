@@ -0,0 +1,7 @@
+# Import an existing Multipass instance by identity (Terraform 1.12+)
+import {
+  to = multipass_instance.example
+  identity = {
+    name = "existing-instance-name"
+  }
+}
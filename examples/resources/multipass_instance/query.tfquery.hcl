@@ -0,0 +1,10 @@
+# Find unmanaged instances with `terraform query` (Terraform 1.14+)
+list "multipass_instance" "web" {
+  provider = multipass
+
+  config {
+    filter {
+      name = "web-*"
+    }
+  }
+}
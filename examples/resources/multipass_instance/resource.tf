@@ -10,6 +10,19 @@ resource "multipass_instance" "custom" {
   cpu    = "2"
   memory = "2G"
   disk   = "10G"
+
+  # Fail plans that would destroy or replace this VM
+  deletion_protection = true
+
+  # Force a wedged guest off instead of blocking the backup and destroy
+  stop_timeout     = "3m"
+  force_stop_after = "1m"
+
+  # Once protection is lifted, keep the two newest copies of the VM
+  snapshot_before_destroy {
+    method = "clone"
+    retain = 2
+  }
 }
 
 # Instance with cloud-init
@@ -21,9 +34,75 @@ resource "multipass_instance" "with_cloud_init" {
   disk       = "20G"
   cloud_init = "./cloud-init.yaml"
 
+  # Delete the VM instead of tainting it when cloud-init outlasts the create timeout
+  on_create_failure = "delete"
+
+  # Reboot in place whenever the kernel parameters pushed into the VM change
+  restart_triggers = {
+    sysctl = filesha256("./sysctl.conf")
+  }
+
+  # Check the result of cloud-init, and drain the VM before it goes away
+  on_create {
+    commands = ["cloud-init status --wait", "systemctl is-active nginx"]
+    timeout  = "10m"
+  }
+
+  before_destroy {
+    commands      = ["sudo systemctl stop nginx"]
+    fail_on_error = false
+  }
+
   # Configure timeouts for longer operations
   timeouts {
     create = "20m" # Cloud-init setup may take longer
     delete = "5m"
   }
-}
\ No newline at end of file
+}
+
+# Instance from a custom-built image, verified before launch
+resource "multipass_instance" "custom_image" {
+  name           = "custom-image-instance"
+  image          = "file:///var/lib/images/custom-jammy.img"
+  image_checksum = "sha256:0f4c5ba0d3d3c6bbd7ab7ebd0e9e0ee1e4aa9d5f1c5e6b7f8a9b0c1d2e3f4a5b"
+}
+
+# Instance from the daily image stream
+resource "multipass_instance" "daily" {
+  name         = "daily-instance"
+  image        = "24.04"
+  image_remote = "daily"
+}
+
+# Instance launched from a blueprint
+resource "multipass_instance" "docker" {
+  name      = "docker-instance"
+  blueprint = "docker"
+}
+
+# Instance configured with a cloud_config block instead of a YAML file
+resource "multipass_instance" "web" {
+  name  = "web-instance"
+  image = "24.04"
+
+  cloud_config {
+    hostname       = "web"
+    timezone       = "Asia/Tokyo"
+    package_update = true
+    packages       = ["nginx"]
+    runcmd         = ["systemctl enable --now nginx"]
+
+    users {
+      name                = "deploy"
+      groups              = ["sudo"]
+      sudo                = "ALL=(ALL) NOPASSWD:ALL"
+      ssh_authorized_keys = [file("~/.ssh/id_ed25519.pub")]
+    }
+
+    write_files {
+      path        = "/var/www/html/index.html"
+      content     = "<h1>Hello from Terraform</h1>\n"
+      permissions = "0644"
+    }
+  }
+}
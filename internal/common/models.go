@@ -1,16 +1,61 @@
 package common
 
-// MultipassInstance represents a Multipass VM instance
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MultipassInstance represents a Multipass VM instance. Details such as
+// load, memory and disks are only reported by `multipass info` for running
+// instances.
 type MultipassInstance struct {
-	Name      string                 `json:"name"`
-	State     string                 `json:"state"`
-	IPv4      []string               `json:"ipv4,omitempty"`
-	Release   string                 `json:"release,omitempty"`
-	ImageHash string                 `json:"image_hash,omitempty"`
-	Load      []float64              `json:"load,omitempty"`
-	DiskUsage string                 `json:"disk_usage,omitempty"`
-	Memory    map[string]interface{} `json:"memory,omitempty"`
-	Mounts    map[string]interface{} `json:"mounts,omitempty"`
+	Name          string                    `json:"name"`
+	State         InstanceState             `json:"state"`
+	IPv4          []string                  `json:"ipv4,omitempty"`
+	Release       string                    `json:"release,omitempty"`
+	ImageHash     string                    `json:"image_hash,omitempty"`
+	ImageRelease  string                    `json:"image_release,omitempty"`
+	CPUCount      *JSONInt                  `json:"cpu_count,omitempty"`
+	SnapshotCount *JSONInt                  `json:"snapshot_count,omitempty"`
+	Load          []float64                 `json:"load,omitempty"`
+	Memory        *MultipassUsage           `json:"memory,omitempty"`
+	Disks         map[string]MultipassUsage `json:"disks,omitempty"`
+	Mounts        map[string]MultipassMount `json:"mounts,omitempty"`
+}
+
+// MultipassUsage is the used and total bytes of memory or a disk
+type MultipassUsage struct {
+	Used  JSONInt `json:"used,omitempty"`
+	Total JSONInt `json:"total,omitempty"`
+}
+
+// MultipassMount is a host directory mounted into an instance, keyed by the
+// target path inside the instance
+type MultipassMount struct {
+	SourcePath  string   `json:"source_path"`
+	UIDMappings []string `json:"uid_mappings,omitempty"`
+	GIDMappings []string `json:"gid_mappings,omitempty"`
+}
+
+// JSONInt is an integer that multipass reports either as a JSON number or
+// as a string, depending on the field and release
+type JSONInt int64
+
+// UnmarshalJSON accepts numbers, numeric strings and empty strings
+func (i *JSONInt) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		*i = 0
+		return nil
+	}
+
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s: %w", data, err)
+	}
+	*i = JSONInt(value)
+	return nil
 }
 
 // MultipassInstanceList represents the list response from multipass list
@@ -28,9 +73,80 @@ type MultipassInstanceInfo struct {
 type LaunchOptions struct {
 	Name      string
 	Image     string
+	Remote    string // Image remote such as "daily", launched as remote:image
+	Blueprint string // Blueprint such as "docker", launched instead of an image
 	CPU       string
 	Memory    string
 	Disk      string
 	CloudInit string
-	Timeout   string // Multipass launch timeout (e.g., "5m", "10m")
+	// CloudInitData is a cloud-config document passed on stdin instead of
+	// the CloudInit file
+	CloudInitData string
+	Timeout       string // Multipass launch timeout (e.g., "5m", "10m")
+}
+
+// MultipassVersion represents the output of multipass version
+type MultipassVersion struct {
+	Client string `json:"multipass"`
+	Daemon string `json:"multipassd,omitempty"`
+}
+
+// InstanceDefaults holds provider-wide settings applied to instances that
+// do not set them
+type InstanceDefaults struct {
+	Image      string
+	CPU        string
+	Memory     string
+	Disk       string
+	CloudInit  string
+	NamePrefix string
+}
+
+// MultipassAlias is a host command that runs a command inside an instance
+type MultipassAlias struct {
+	Name             string `json:"alias,omitempty"`
+	Instance         string `json:"instance"`
+	Command          string `json:"command"`
+	WorkingDirectory string `json:"working-directory,omitempty"`
+}
+
+// MultipassAliasList represents the output of multipass aliases. Multipass
+// 1.11 and later group aliases by context, earlier releases list them
+// directly.
+type MultipassAliasList struct {
+	ActiveContext string                               `json:"active_context,omitempty"`
+	Contexts      map[string]map[string]MultipassAlias `json:"contexts,omitempty"`
+	Aliases       []MultipassAlias                     `json:"aliases,omitempty"`
+}
+
+// MultipassSnapshot describes a snapshot in multipass list --snapshots
+type MultipassSnapshot struct {
+	Comment string `json:"comment,omitempty"`
+	Parent  string `json:"parent,omitempty"`
+}
+
+// MultipassSnapshotList represents the output of multipass list --snapshots,
+// keyed by instance and then by snapshot name
+type MultipassSnapshotList struct {
+	Info   map[string]map[string]MultipassSnapshot `json:"info"`
+	Errors []string                                `json:"errors,omitempty"`
+}
+
+// MultipassImage describes an image or blueprint in multipass find
+type MultipassImage struct {
+	OS      string   `json:"os,omitempty"`
+	Release string   `json:"release,omitempty"`
+	Version string   `json:"version,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	Remote  string   `json:"remote,omitempty"`
+}
+
+// MultipassFindResult represents the output of multipass find. Images are
+// keyed by name, prefixed with the remote outside the default one. Releases
+// from 1.14 on list blueprints under a deprecation notice.
+type MultipassFindResult struct {
+	Images               map[string]MultipassImage `json:"images"`
+	Blueprints           map[string]MultipassImage `json:"blueprints,omitempty"`
+	DeprecatedBlueprints map[string]MultipassImage `json:"blueprints (deprecated),omitempty"`
+	Errors               []string                  `json:"errors,omitempty"`
 }
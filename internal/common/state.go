@@ -0,0 +1,33 @@
+package common
+
+// InstanceState is the state of an instance as reported by multipass
+type InstanceState string
+
+// Instance states reported by multipass
+const (
+	StateRunning         InstanceState = "Running"
+	StateStopped         InstanceState = "Stopped"
+	StateSuspended       InstanceState = "Suspended"
+	StateDeleted         InstanceState = "Deleted"
+	StateStarting        InstanceState = "Starting"
+	StateRestarting      InstanceState = "Restarting"
+	StateDelayedShutdown InstanceState = "Delayed Shutdown"
+	StateSuspending      InstanceState = "Suspending"
+	StateUnknown         InstanceState = "Unknown"
+)
+
+// IsStable reports whether an instance stays in this state until it is
+// asked to change. Instances in any other state are in transition, or in
+// the case of Unknown cannot be reached by the daemon yet.
+func (s InstanceState) IsStable() bool {
+	switch s {
+	case StateRunning, StateStopped, StateSuspended, StateDeleted:
+		return true
+	}
+	return false
+}
+
+// String returns the state as reported by multipass
+func (s InstanceState) String() string {
+	return string(s)
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AliasResource{}
+var _ resource.ResourceWithImportState = &AliasResource{}
+
+func NewAliasResource() resource.Resource {
+	return &AliasResource{}
+}
+
+// AliasResource defines the resource implementation.
+type AliasResource struct {
+	client *MultipassClient
+}
+
+// AliasResourceModel describes the resource data model.
+type AliasResourceModel struct {
+	Id               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Instance         types.String `tfsdk:"instance"`
+	Command          types.String `tfsdk:"command"`
+	WorkingDirectory types.String `tfsdk:"working_directory"`
+}
+
+func (r *AliasResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alias"
+}
+
+func (r *AliasResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Multipass alias resource. Makes a command of an instance available on the host, e.g. `kubectl` running inside a Kubernetes VM.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Alias identifier (same as name)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Alias name, the command run on the host",
+				Required:            true,
+				Validators: []validator.String{
+					aliasNameValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"instance": schema.StringAttribute{
+				MarkdownDescription: "Instance the command runs in",
+				Required:            true,
+				Validators: []validator.String{
+					instanceNameValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"command": schema.StringAttribute{
+				MarkdownDescription: "Command to run inside the instance",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"working_directory": schema.StringAttribute{
+				MarkdownDescription: "`map` runs the command in the instance directory mounted from the host working directory, `default` in the home directory of the instance user. Defaults to `map`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(aliasWorkingDirectoryMap),
+				Validators: []validator.String{
+					oneOfValidator("working directory mode", aliasWorkingDirectoryMap, aliasWorkingDirectoryDefault),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AliasResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*MultipassClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *MultipassClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AliasResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AliasResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.requireCapability(CapabilityAliases, path.Root("name"))...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "creating multipass alias", map[string]interface{}{"name": data.Name.ValueString()})
+
+	err := r.client.CreateAlias(ctx, common.MultipassAlias{
+		Name:             data.Name.ValueString(),
+		Instance:         data.Instance.ValueString(),
+		Command:          data.Command.ValueString(),
+		WorkingDirectory: data.WorkingDirectory.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create alias, got error: %s", err))
+		return
+	}
+
+	data.Id = data.Name
+
+	tflog.Trace(ctx, "created multipass alias")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AliasResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AliasResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	alias, err := r.client.GetAlias(ctx, data.Name.ValueString())
+	if err != nil {
+		if errors.Is(err, ErrAliasNotFound) {
+			// Alias was removed outside of Terraform, remove from state
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read alias, got error: %s", err))
+		return
+	}
+
+	data.Id = types.StringValue(alias.Name)
+	data.Instance = types.StringValue(alias.Instance)
+	data.Command = types.StringValue(alias.Command)
+	data.WorkingDirectory = types.StringValue(alias.WorkingDirectory)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AliasResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AliasResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every argument requires replacement, so there is nothing to change
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AliasResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AliasResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "deleting multipass alias", map[string]interface{}{"name": data.Name.ValueString()})
+
+	err := r.client.DeleteAlias(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete alias, got error: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "deleted multipass alias")
+}
+
+func (r *AliasResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by alias name
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAliasResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAliasResourceConfig("map"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("multipass_alias.test", "id", "test-alias-ls"),
+					resource.TestCheckResourceAttr("multipass_alias.test", "instance", "test-alias"),
+					resource.TestCheckResourceAttr("multipass_alias.test", "command", "ls"),
+					resource.TestCheckResourceAttr("multipass_alias.test", "working_directory", "map"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "multipass_alias.test",
+				ImportState:       true,
+				ImportStateId:     "test-alias-ls",
+				ImportStateVerify: true,
+			},
+			// Changing the working directory mode replaces the alias
+			{
+				Config: testAccAliasResourceConfig("default"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("multipass_alias.test", "working_directory", "default"),
+					resource.TestCheckResourceAttr("data.multipass_aliases.test", "aliases.#", "1"),
+					resource.TestCheckResourceAttr("data.multipass_aliases.test", "aliases.0.name", "test-alias-ls"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccAliasResourceConfig(workingDirectory string) string {
+	return fmt.Sprintf(`
+resource "multipass_instance" "test" {
+  name = "test-alias"
+}
+
+resource "multipass_alias" "test" {
+  name              = "test-alias-ls"
+  instance          = multipass_instance.test.name
+  command           = "ls"
+  working_directory = %q
+}
+
+data "multipass_aliases" "test" {
+  instance = multipass_instance.test.name
+
+  depends_on = [multipass_alias.test]
+}
+`, workingDirectory)
+}
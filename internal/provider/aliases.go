@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Working directory modes of an alias. With "map" the alias runs in the
+// instance directory that corresponds to the host working directory, with
+// "default" it runs in the home directory of the instance user.
+const (
+	aliasWorkingDirectoryMap     = "map"
+	aliasWorkingDirectoryDefault = "default"
+)
+
+// ErrAliasNotFound is returned when multipass has no alias with the
+// requested name
+var ErrAliasNotFound = errors.New("alias not found")
+
+// isAliasNotFoundOutput reports whether multipass stderr output says that
+// the requested alias does not exist
+func isAliasNotFoundOutput(stderr string) bool {
+	return strings.Contains(strings.ToLower(stderr), "nonexistent alias")
+}
+
+// ListAliases returns the aliases of the active context, sorted by name
+func (c *MultipassClient) ListAliases(ctx context.Context) ([]common.MultipassAlias, error) {
+	result, err := c.runWithRetry(ctx, "aliases", "--format", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w, output: %s", err, string(result.Stderr))
+	}
+
+	var list common.MultipassAliasList
+	if err := json.Unmarshal(result.Stdout, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse alias list: %w", err)
+	}
+
+	aliases := list.Aliases
+	if list.Contexts != nil {
+		aliases = nil
+		for name, alias := range list.Contexts[list.ActiveContext] {
+			alias.Name = name
+			aliases = append(aliases, alias)
+		}
+	}
+
+	for i := range aliases {
+		if aliases[i].WorkingDirectory == "" {
+			aliases[i].WorkingDirectory = aliasWorkingDirectoryMap
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Name < aliases[j].Name
+	})
+
+	return aliases, nil
+}
+
+// GetAlias returns the alias with the given name
+func (c *MultipassClient) GetAlias(ctx context.Context, name string) (*common.MultipassAlias, error) {
+	aliases, err := c.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		if alias.Name == name {
+			return &alias, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+}
+
+// CreateAlias runs `multipass alias` to make alias.Command of alias.Instance
+// available on the host as alias.Name
+func (c *MultipassClient) CreateAlias(ctx context.Context, alias common.MultipassAlias) error {
+	args := []string{"alias", alias.Instance + ":" + alias.Command, alias.Name}
+	if alias.WorkingDirectory == aliasWorkingDirectoryDefault {
+		args = append(args, "--no-map-working-directory")
+	}
+
+	// Creating an alias that already exists fails, so this is not retried
+	result, err := c.run(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create alias: %w, output: %s", err, result.Output())
+	}
+
+	return nil
+}
+
+// DeleteAlias removes an alias. Aliases that no longer exist are ignored.
+func (c *MultipassClient) DeleteAlias(ctx context.Context, name string) error {
+	result, err := c.run(ctx, "unalias", name)
+	if err != nil {
+		if isAliasNotFoundOutput(string(result.Stderr)) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete alias: %w, output: %s", err, result.Output())
+	}
+
+	return nil
+}
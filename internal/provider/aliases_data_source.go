@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AliasesDataSource{}
+
+func NewAliasesDataSource() datasource.DataSource {
+	return &AliasesDataSource{}
+}
+
+// AliasesDataSource defines the data source implementation.
+type AliasesDataSource struct {
+	client *MultipassClient
+}
+
+// AliasesDataSourceModel describes the data source data model.
+type AliasesDataSourceModel struct {
+	Id       types.String     `tfsdk:"id"`
+	Instance types.String     `tfsdk:"instance"`
+	Aliases  []AliasDataModel `tfsdk:"aliases"`
+}
+
+// AliasDataModel describes a single alias.
+type AliasDataModel struct {
+	Name             types.String `tfsdk:"name"`
+	Instance         types.String `tfsdk:"instance"`
+	Command          types.String `tfsdk:"command"`
+	WorkingDirectory types.String `tfsdk:"working_directory"`
+}
+
+func (d *AliasesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aliases"
+}
+
+func (d *AliasesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Multipass aliases data source. Lists the aliases of the active multipass context.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier",
+				Computed:            true,
+			},
+			"instance": schema.StringAttribute{
+				MarkdownDescription: "Only list aliases running in this instance",
+				Optional:            true,
+			},
+			"aliases": schema.ListNestedAttribute{
+				MarkdownDescription: "Aliases sorted by name",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Alias name",
+							Computed:            true,
+						},
+						"instance": schema.StringAttribute{
+							MarkdownDescription: "Instance the command runs in",
+							Computed:            true,
+						},
+						"command": schema.StringAttribute{
+							MarkdownDescription: "Command run inside the instance",
+							Computed:            true,
+						},
+						"working_directory": schema.StringAttribute{
+							MarkdownDescription: "Working directory mode, `map` or `default`",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AliasesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*MultipassClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *MultipassClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AliasesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AliasesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "reading multipass aliases")
+
+	aliases, err := d.client.ListAliases(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list aliases, got error: %s", err))
+		return
+	}
+
+	data.Aliases = []AliasDataModel{}
+	for _, alias := range aliases {
+		if !data.Instance.IsNull() && alias.Instance != data.Instance.ValueString() {
+			continue
+		}
+		data.Aliases = append(data.Aliases, AliasDataModel{
+			Name:             types.StringValue(alias.Name),
+			Instance:         types.StringValue(alias.Instance),
+			Command:          types.StringValue(alias.Command),
+			WorkingDirectory: types.StringValue(alias.WorkingDirectory),
+		})
+	}
+
+	data.Id = types.StringValue("aliases")
+	if !data.Instance.IsNull() {
+		data.Id = data.Instance
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
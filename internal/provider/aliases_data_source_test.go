@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAliasesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAliasesDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.multipass_aliases.test", "id", "aliases"),
+					resource.TestCheckResourceAttrSet("data.multipass_aliases.test", "aliases.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAliasesDataSourceConfig = `
+data "multipass_aliases" "test" {}
+`
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeAliasBinary creates a fake multipass binary that prints aliasesJSON
+// for `multipass aliases` and records every invocation
+func writeAliasBinary(t *testing.T, aliasesJSON string) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, fmt.Sprintf(`if [ "$1" = "aliases" ]; then
+  echo '%s'
+  exit 0
+fi
+if [ "$1" = "unalias" ] && [ "$2" = "missing" ]; then
+  echo 'Nonexistent alias: missing.' >&2
+  exit 2
+fi
+exit 0
+`, aliasesJSON))
+}
+
+func TestListAliases(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+	}{
+		{
+			name:   "Contexts",
+			output: `{"active_context": "default", "contexts": {"default": {"kc": {"command": "kubectl", "instance": "k8s", "working-directory": "default"}, "helm": {"command": "helm", "instance": "k8s", "working-directory": "map"}}, "other": {"ls": {"command": "ls", "instance": "web"}}}}`,
+		},
+		{
+			name:   "Legacy list",
+			output: `{"aliases": [{"alias": "kc", "command": "kubectl", "instance": "k8s", "working-directory": "default"}, {"alias": "helm", "command": "helm", "instance": "k8s"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binary, _ := writeAliasBinary(t, tc.output)
+			client := NewMultipassClient(binary)
+
+			aliases, err := client.ListAliases(context.Background())
+			if err != nil {
+				t.Fatalf("ListAliases() error = %v", err)
+			}
+
+			want := []common.MultipassAlias{
+				{Name: "helm", Instance: "k8s", Command: "helm", WorkingDirectory: "map"},
+				{Name: "kc", Instance: "k8s", Command: "kubectl", WorkingDirectory: "default"},
+			}
+			if len(aliases) != len(want) {
+				t.Fatalf("ListAliases() = %+v, want %+v", aliases, want)
+			}
+			for i := range want {
+				if aliases[i] != want[i] {
+					t.Errorf("ListAliases()[%d] = %+v, want %+v", i, aliases[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetAliasNotFound(t *testing.T) {
+	binary, _ := writeAliasBinary(t, `{"active_context": "default", "contexts": {"default": {}}}`)
+	client := NewMultipassClient(binary)
+
+	_, err := client.GetAlias(context.Background(), "kc")
+	if !errors.Is(err, ErrAliasNotFound) {
+		t.Errorf("Expected ErrAliasNotFound, got %v", err)
+	}
+}
+
+func TestCreateAlias(t *testing.T) {
+	binary, calls := writeAliasBinary(t, `{}`)
+	client := NewMultipassClient(binary)
+
+	aliases := []common.MultipassAlias{
+		{Name: "kc", Instance: "k8s", Command: "kubectl", WorkingDirectory: "map"},
+		{Name: "home-ls", Instance: "web", Command: "ls", WorkingDirectory: "default"},
+	}
+	for _, alias := range aliases {
+		if err := client.CreateAlias(context.Background(), alias); err != nil {
+			t.Fatalf("CreateAlias() error = %v", err)
+		}
+	}
+
+	got := readCalls(t, calls)
+	want := []string{
+		"alias k8s:kubectl kc",
+		"alias web:ls home-ls --no-map-working-directory",
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected calls %q, got %q", want, got)
+	}
+}
+
+func TestDeleteAliasMissing(t *testing.T) {
+	binary, _ := writeAliasBinary(t, `{}`)
+	client := NewMultipassClient(binary)
+
+	if err := client.DeleteAlias(context.Background(), "missing"); err != nil {
+		t.Errorf("Expected a missing alias to be ignored, got %v", err)
+	}
+	if err := client.DeleteAlias(context.Background(), "kc"); err != nil {
+		t.Errorf("DeleteAlias() error = %v", err)
+	}
+}
+
+func TestValidateAliasName(t *testing.T) {
+	for _, name := range []string{"kc", "kubectl", "k8s.helm", "my_tool-2"} {
+		if err := validateAliasName(name); err != nil {
+			t.Errorf("validateAliasName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-kc", ".hidden", "a/b", "with space"} {
+		if err := validateAliasName(name); err == nil {
+			t.Errorf("validateAliasName(%q) expected an error", name)
+		}
+	}
+}
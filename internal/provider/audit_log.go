@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// logSubsystem is the tflog subsystem used for multipass CLI invocations
+const logSubsystem = "multipass_cli"
+
+// redactedValue replaces sensitive values in logged output
+const redactedValue = "[REDACTED]"
+
+// Secrets that may show up in multipass output, for example when cloud-init
+// or exec output is echoed back on failure
+var (
+	privateKeyPattern       = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+	secretAssignmentPattern = regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api[_-]?key)["']?\s*[:=]\s*["']?)[^\s"',]+`)
+)
+
+// redactOutput masks secrets in command output before it is logged
+func redactOutput(output string) string {
+	output = privateKeyPattern.ReplaceAllString(output, redactedValue)
+	return secretAssignmentPattern.ReplaceAllString(output, "${1}"+redactedValue)
+}
+
+// auditRecord is a single JSON line written to the audit log
+type auditRecord struct {
+	Time       string   `json:"time"`
+	Binary     string   `json:"binary"`
+	Args       []string `json:"args"`
+	DurationMS int64    `json:"duration_ms"`
+	ExitCode   int      `json:"exit_code"`
+	Error      string   `json:"error,omitempty"`
+	Stderr     string   `json:"stderr,omitempty"`
+}
+
+// auditLogger appends a JSON line for every executed multipass command
+type auditLogger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// newAuditLogger creates an audit logger writing to path, or nil when path is empty
+func newAuditLogger(path string) *auditLogger {
+	if path == "" {
+		return nil
+	}
+	return &auditLogger{path: path}
+}
+
+// Write appends a record to the audit log file, creating it if needed
+func (l *auditLogger) Write(record auditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// newAuditRecord builds an audit record for a finished command
+func newAuditRecord(binary string, args []string, started time.Time, duration time.Duration, exitCode int, err error, stderr string) auditRecord {
+	record := auditRecord{
+		Time:       started.UTC().Format(time.RFC3339Nano),
+		Binary:     binary,
+		Args:       args,
+		DurationMS: duration.Milliseconds(),
+		ExitCode:   exitCode,
+		Stderr:     redactOutput(stderr),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Capability names a multipass feature that is only available from a
+// certain release onwards
+type Capability string
+
+const (
+	CapabilityDeletePurge Capability = "delete_purge"
+	CapabilitySnapshots   Capability = "snapshots"
+	CapabilityClone       Capability = "clone"
+	CapabilityAliases     Capability = "aliases"
+	CapabilityForceStop   Capability = "force_stop"
+)
+
+// oldestMultipassVersion is the oldest multipass release the provider works
+// with. Capabilities it already provides never depend on version detection.
+const oldestMultipassVersion = "1.0.0"
+
+// capabilityMinVersions lists the first multipass release supporting each capability
+var capabilityMinVersions = map[Capability]string{
+	CapabilityDeletePurge: oldestMultipassVersion,
+	CapabilitySnapshots:   "1.13.0",
+	CapabilityClone:       "1.15.0",
+	CapabilityAliases:     "1.10.0",
+	CapabilityForceStop:   "1.13.0",
+}
+
+// GetVersion runs `multipass version` and returns the client and daemon versions
+func (c *MultipassClient) GetVersion(ctx context.Context) (*common.MultipassVersion, error) {
+	result, err := c.runWithRetry(ctx, "version", "--format", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get multipass version: %w, output: %s", err, string(result.Stderr))
+	}
+
+	var version common.MultipassVersion
+	if err := json.Unmarshal(result.Stdout, &version); err != nil {
+		return nil, fmt.Errorf("failed to parse multipass version: %w", err)
+	}
+
+	return &version, nil
+}
+
+// DetectVersion queries the multipass version once and keeps it on the client
+// for capability checks. Later calls return the stored version.
+func (c *MultipassClient) DetectVersion(ctx context.Context) (*common.MultipassVersion, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.version != nil {
+		return c.version, nil
+	}
+
+	version, err := c.GetVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.version = version
+	return version, nil
+}
+
+// Version returns the detected multipass version, or nil if it is unknown
+func (c *MultipassClient) Version() *common.MultipassVersion {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	return c.version
+}
+
+// Supports reports whether the detected multipass release provides the
+// capability. The daemon version is used when known since it performs the
+// operations. With an undetected or unparsable version, only the
+// capabilities of the oldest supported release are reported as supported.
+func (c *MultipassClient) Supports(capability Capability) bool {
+	version := c.Version()
+	if version == nil {
+		return capabilityMinVersions[capability] == oldestMultipassVersion
+	}
+
+	installed := version.Daemon
+	if installed == "" {
+		installed = version.Client
+	}
+
+	return versionSupports(installed, capability)
+}
+
+// versionSupports reports whether the multipass release installed provides capability
+func versionSupports(installed string, capability Capability) bool {
+	minimum, ok := capabilityMinVersions[capability]
+	if !ok {
+		return false
+	}
+
+	current, err := goversion.NewVersion(installed)
+	if err != nil {
+		return minimum == oldestMultipassVersion
+	}
+
+	// Development builds such as "1.14.0-dev.1209+g5b2c7f7c" carry the
+	// features of the release they lead up to
+	return current.Core().GreaterThanOrEqual(goversion.Must(goversion.NewVersion(minimum)))
+}
+
+// requireCapability returns an error diagnostic on attrPath when the
+// configuration uses a feature that the installed multipass does not
+// support. Nothing is reported while the version is unknown.
+func (c *MultipassClient) requireCapability(capability Capability, attrPath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	version := c.Version()
+	if version == nil || c.Supports(capability) {
+		return diags
+	}
+
+	installed := version.Daemon
+	if installed == "" {
+		installed = version.Client
+	}
+
+	diags.AddAttributeError(attrPath, "Unsupported Multipass Feature",
+		fmt.Sprintf("This configuration uses %s, which requires multipass %s or later, but multipass %s is installed. "+
+			"Upgrade multipass or remove the setting.", capability, capabilityMinVersions[capability], installed))
+
+	return diags
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeVersionBinary creates a fake multipass that reports the given versions
+func writeVersionBinary(t *testing.T, client string, daemon string) string {
+	t.Helper()
+
+	binary, _ := writeFakeMultipass(t, fmt.Sprintf(`if [ "$1" = "version" ]; then
+  echo '{"multipass": "%s", "multipassd": "%s"}'
+  exit 0
+fi
+exit 1
+`, client, daemon))
+	return binary
+}
+
+// TestVersionSupports tests capability checks against multipass releases
+func TestVersionSupports(t *testing.T) {
+	testCases := []struct {
+		name       string
+		installed  string
+		capability Capability
+		want       bool
+	}{
+		{"Snapshots on 1.13", "1.13.0", CapabilitySnapshots, true},
+		{"Snapshots on 1.12", "1.12.2", CapabilitySnapshots, false},
+		{"Snapshots with platform suffix", "1.13.1+mac", CapabilitySnapshots, true},
+		{"Clone on dev build", "1.15.0-dev.2929+g5b2c7f7c", CapabilityClone, true},
+		{"Clone on 1.14", "1.14.1", CapabilityClone, false},
+		{"Delete purge", "1.8.0", CapabilityDeletePurge, true},
+		{"Unparsable version", "unknown", CapabilitySnapshots, false},
+		{"Delete purge on unparsable version", "unknown", CapabilityDeletePurge, true},
+		{"Unknown capability", "1.15.0", Capability("teleport"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := versionSupports(tc.installed, tc.capability); got != tc.want {
+				t.Errorf("versionSupports(%q, %q) = %v, want %v", tc.installed, tc.capability, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDetectVersion tests parsing and storing of the multipass version
+func TestDetectVersion(t *testing.T) {
+	client := NewMultipassClient(writeVersionBinary(t, "1.14.1+mac", "1.13.0+mac"))
+
+	if client.Version() != nil {
+		t.Error("Expected version to be unknown before detection")
+	}
+
+	version, err := client.DetectVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if version.Client != "1.14.1+mac" || version.Daemon != "1.13.0+mac" {
+		t.Errorf("Unexpected version: %+v", version)
+	}
+
+	// The daemon version decides, since it performs the operations
+	if !client.Supports(CapabilitySnapshots) {
+		t.Error("Expected snapshots to be supported")
+	}
+	if client.Supports(CapabilityClone) {
+		t.Error("Expected clone to be unsupported")
+	}
+}
+
+// TestDetectVersionError tests version detection with an invalid binary
+func TestDetectVersionError(t *testing.T) {
+	client := NewMultipassClient("/non/existent/binary")
+
+	if _, err := client.DetectVersion(context.Background()); err == nil {
+		t.Error("Expected error, got nil")
+	}
+	if client.Supports(CapabilitySnapshots) {
+		t.Error("Expected capabilities to be unsupported without a version")
+	}
+	// Every supported release purges a single instance
+	if !client.Supports(CapabilityDeletePurge) {
+		t.Error("Expected delete purge to be supported without a version")
+	}
+}
+
+// TestRequireCapability tests diagnostics for unsupported features
+func TestRequireCapability(t *testing.T) {
+	client := NewMultipassClient("multipass")
+
+	if diags := client.requireCapability(CapabilitySnapshots, path.Root("snapshot")); diags.HasError() {
+		t.Error("Expected no error while the version is unknown")
+	}
+
+	client.version = &common.MultipassVersion{Client: "1.12.0"}
+	diags := client.requireCapability(CapabilitySnapshots, path.Root("snapshot"))
+	if !diags.HasError() {
+		t.Fatal("Expected error for unsupported feature")
+	}
+
+	client.version = &common.MultipassVersion{Client: "1.13.0"}
+	if diags := client.requireCapability(CapabilitySnapshots, path.Root("snapshot")); diags.HasError() {
+		t.Errorf("Unexpected error: %v", diags)
+	}
+}
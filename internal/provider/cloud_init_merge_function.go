@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"gopkg.in/yaml.v3"
+)
+
+// cloudConfigHeader marks user data as a cloud-config document
+const cloudConfigHeader = "#cloud-config\n"
+
+var _ function.Function = &CloudInitMergeFunction{}
+
+func NewCloudInitMergeFunction() function.Function {
+	return &CloudInitMergeFunction{}
+}
+
+// CloudInitMergeFunction deep-merges two cloud-config documents.
+type CloudInitMergeFunction struct{}
+
+func (f *CloudInitMergeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cloud_init_merge"
+}
+
+func (f *CloudInitMergeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Deep-merge two cloud-config documents",
+		MarkdownDescription: "Merges `override` into `base` the way multipass merges its own cloud-init data into user data: mappings are merged " +
+			"recursively, lists are appended and scalar values from `override` replace those in `base`. The result starts with `#cloud-config`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "base",
+				MarkdownDescription: "Base cloud-config YAML",
+			},
+			function.StringParameter{
+				Name:                "override",
+				MarkdownDescription: "Cloud-config YAML merged on top of `base`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *CloudInitMergeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var base, override string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &base, &override))
+	if resp.Error != nil {
+		return
+	}
+
+	baseConfig, err := parseCloudConfig(base)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	overrideConfig, err := parseCloudConfig(override)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	merged, err := yaml.Marshal(mergeCloudConfig(baseConfig, overrideConfig))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewFuncError(fmt.Sprintf("failed to render merged cloud-config: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, cloudConfigHeader+string(merged)))
+}
+
+// parseCloudConfig decodes a cloud-config document, treating an empty one as
+// an empty mapping
+func parseCloudConfig(document string) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(document), &config); err != nil {
+		return nil, fmt.Errorf("invalid cloud-config YAML: %s", err)
+	}
+	return config, nil
+}
+
+// mergeCloudConfig merges override into base: mappings recursively, lists by
+// appending and everything else by replacing
+func mergeCloudConfig(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range override {
+		existing, exists := merged[key]
+		if !exists {
+			merged[key] = value
+			continue
+		}
+
+		switch overrideValue := value.(type) {
+		case map[string]interface{}:
+			if baseValue, ok := existing.(map[string]interface{}); ok {
+				merged[key] = mergeCloudConfig(baseValue, overrideValue)
+				continue
+			}
+		case []interface{}:
+			if baseValue, ok := existing.([]interface{}); ok {
+				merged[key] = append(append([]interface{}{}, baseValue...), overrideValue...)
+				continue
+			}
+		}
+
+		merged[key] = value
+	}
+
+	return merged
+}
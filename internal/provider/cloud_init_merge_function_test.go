@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestCloudInitMergeFunction(t *testing.T) {
+	base := `#cloud-config
+package_update: true
+packages:
+  - git
+users:
+  - default
+write_files:
+  - path: /etc/motd
+    content: base
+runcmd:
+  - echo base
+`
+	override := `#cloud-config
+package_update: false
+packages:
+  - curl
+timezone: UTC
+`
+
+	want := `#cloud-config
+package_update: false
+packages:
+    - git
+    - curl
+runcmd:
+    - echo base
+timezone: UTC
+users:
+    - default
+write_files:
+    - content: base
+      path: /etc/motd
+`
+
+	got, funcErr := runFunction(t, NewCloudInitMergeFunction(), types.StringValue(base), types.StringValue(override))
+	if funcErr != nil {
+		t.Fatalf("cloud_init_merge error = %s", funcErr)
+	}
+	if !got.Equal(types.StringValue(want)) {
+		t.Errorf("cloud_init_merge =\n%s\nwant\n%s", got.(types.String).ValueString(), want)
+	}
+}
+
+func TestCloudInitMergeFunctionNested(t *testing.T) {
+	base := "apt:\n  sources:\n    a: {source: x}\n  preserve_sources_list: true\n"
+	override := "apt:\n  sources:\n    b: {source: y}\n"
+
+	want := "#cloud-config\napt:\n    preserve_sources_list: true\n    sources:\n        a:\n            source: x\n        b:\n            source: \"y\"\n"
+
+	got, funcErr := runFunction(t, NewCloudInitMergeFunction(), types.StringValue(base), types.StringValue(override))
+	if funcErr != nil {
+		t.Fatalf("cloud_init_merge error = %s", funcErr)
+	}
+	if !got.Equal(types.StringValue(want)) {
+		t.Errorf("cloud_init_merge =\n%s\nwant\n%s", got.(types.String).ValueString(), want)
+	}
+}
+
+func TestCloudInitMergeFunctionInvalid(t *testing.T) {
+	if _, funcErr := runFunction(t, NewCloudInitMergeFunction(), types.StringValue("packages: ["), types.StringValue("")); funcErr == nil {
+		t.Error("expected error for invalid base YAML")
+	}
+	if _, funcErr := runFunction(t, NewCloudInitMergeFunction(), types.StringValue(""), types.StringValue("- not a mapping")); funcErr == nil {
+		t.Error("expected error for non-mapping override")
+	}
+}
+
+func TestAccCloudInitMergeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(testAccFunctionsTerraformVersion),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::multipass::cloud_init_merge("packages: [git]", "packages: [curl]")
+}
+`,
+				Check: resource.TestCheckOutput("test", "#cloud-config\npackages:\n    - git\n    - curl\n"),
+			},
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -153,7 +154,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				err := client.Launch(opts)
+				err := client.Launch(context.Background(), opts)
 				results <- err
 			}()
 		}
@@ -181,7 +182,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 	// Test concurrent operations on the same instance
 	t.Run("ConcurrentOperations", func(t *testing.T) {
 		// First ensure the instance exists (ignore error if it already exists)
-		client.Launch(opts)
+		client.Launch(context.Background(), opts)
 
 		var wg sync.WaitGroup
 		operationResults := make(chan error, 6)
@@ -190,7 +191,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := client.StartInstance(instanceName)
+			err := client.StartInstance(context.Background(), instanceName)
 			operationResults <- err
 		}()
 
@@ -198,7 +199,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := client.StopInstance(instanceName)
+			err := client.StopInstance(context.Background(), instanceName)
 			operationResults <- err
 		}()
 
@@ -206,7 +207,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := client.RestartInstance(instanceName)
+			err := client.RestartInstance(context.Background(), instanceName)
 			operationResults <- err
 		}()
 
@@ -214,7 +215,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, err := client.GetInstance(instanceName)
+			_, err := client.GetInstance(context.Background(), instanceName)
 			operationResults <- err
 		}()
 
@@ -222,7 +223,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, err := client.ListInstances()
+			_, err := client.ListInstances(context.Background())
 			operationResults <- err
 		}()
 
@@ -230,7 +231,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := client.SuspendInstance(instanceName)
+			err := client.SuspendInstance(context.Background(), instanceName)
 			operationResults <- err
 		}()
 
@@ -247,7 +248,7 @@ func TestRaceConditionInstanceOperations(t *testing.T) {
 		}
 
 		// Clean up
-		client.DeleteInstance(instanceName, true)
+		client.DeleteInstance(context.Background(), instanceName, true)
 	})
 }
 
@@ -278,11 +279,11 @@ func TestDeadlockPrevention(t *testing.T) {
 				}
 
 				// Perform multiple operations on each instance
-				client.Launch(opts)
-				client.GetInstance(instanceName)
-				client.StartInstance(instanceName)
-				client.StopInstance(instanceName)
-				client.DeleteInstance(instanceName, true)
+				client.Launch(context.Background(), opts)
+				client.GetInstance(context.Background(), instanceName)
+				client.StartInstance(context.Background(), instanceName)
+				client.StopInstance(context.Background(), instanceName)
+				client.DeleteInstance(context.Background(), instanceName, true)
 			}(i)
 		}
 
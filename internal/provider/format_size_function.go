@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &FormatSizeFunction{}
+
+func NewFormatSizeFunction() function.Function {
+	return &FormatSizeFunction{}
+}
+
+// FormatSizeFunction converts bytes to multipass size strings.
+type FormatSizeFunction struct{}
+
+func (f *FormatSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_size"
+}
+
+func (f *FormatSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert bytes to a multipass size",
+		MarkdownDescription: "Converts a number of bytes to the shortest multipass size that represents it exactly, e.g. `1610612736` becomes `1536M`. The result can be used for `memory` and `disk`.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:                "bytes",
+				MarkdownDescription: "Number of bytes",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bytes int64
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &bytes))
+	if resp.Error != nil {
+		return
+	}
+
+	size, err := formatSize(bytes)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, size))
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestFormatSizeFunction(t *testing.T) {
+	got, funcErr := runFunction(t, NewFormatSizeFunction(), types.Int64Value(3<<29))
+	if funcErr != nil {
+		t.Fatalf("format_size error = %s", funcErr)
+	}
+	if !got.Equal(types.StringValue("1536M")) {
+		t.Errorf("format_size(1610612736) = %s, want \"1536M\"", got)
+	}
+
+	if _, funcErr := runFunction(t, NewFormatSizeFunction(), types.Int64Value(-1)); funcErr == nil {
+		t.Error("expected error for negative size")
+	}
+}
+
+func TestAccFormatSizeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(testAccFunctionsTerraformVersion),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::multipass::format_size(2147483648)
+}
+`,
+				Check: resource.TestCheckOutput("test", "2G"),
+			},
+		},
+	})
+}
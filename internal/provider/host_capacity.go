@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// Capacity check modes
+const (
+	capacityCheckOff   = "off"
+	capacityCheckWarn  = "warn"
+	capacityCheckError = "error"
+)
+
+// Allocations multipass uses when an instance does not set them
+const (
+	multipassDefaultCPUs   = 1
+	multipassDefaultMemory = 1 << 30
+	multipassDefaultDisk   = 5 << 30
+)
+
+// HostCapacity describes the resources of the machine running multipass
+type HostCapacity struct {
+	CPUs            int64
+	TotalMemory     int64
+	AvailableMemory int64
+	TotalDisk       int64
+	AvailableDisk   int64
+	StoragePath     string
+}
+
+// CapacityCheckConfig controls the plan-time host capacity check
+type CapacityCheckConfig struct {
+	Mode                  string
+	CPUOvercommitRatio    float64
+	MemoryOvercommitRatio float64
+	DiskOvercommitRatio   float64
+	StoragePath           string
+}
+
+// DefaultCapacityCheckConfig warns when planned instances need more than the host has
+func DefaultCapacityCheckConfig() CapacityCheckConfig {
+	return CapacityCheckConfig{
+		Mode:                  capacityCheckWarn,
+		CPUOvercommitRatio:    1.0,
+		MemoryOvercommitRatio: 1.0,
+		DiskOvercommitRatio:   1.0,
+	}
+}
+
+// Validate checks that the capacity check settings are usable
+func (c CapacityCheckConfig) Validate() error {
+	switch c.Mode {
+	case capacityCheckOff, capacityCheckWarn, capacityCheckError:
+	default:
+		return fmt.Errorf("mode must be one of %q, %q or %q, got %q", capacityCheckOff, capacityCheckWarn, capacityCheckError, c.Mode)
+	}
+	if c.CPUOvercommitRatio <= 0 || c.MemoryOvercommitRatio <= 0 || c.DiskOvercommitRatio <= 0 {
+		return fmt.Errorf("overcommit ratios must be greater than 0")
+	}
+	return nil
+}
+
+// defaultStoragePath returns where multipass keeps instance images on this platform
+func defaultStoragePath() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/var/root/Library/Application Support/multipassd"
+	case "windows":
+		return filepath.Join(os.Getenv("ProgramData"), "Multipass")
+	default:
+		return "/var/snap/multipass/common/data/multipassd"
+	}
+}
+
+// readHostCapacity inspects the CPUs, memory and the disk holding storagePath
+func readHostCapacity(storagePath string) (*HostCapacity, error) {
+	if storagePath == "" {
+		storagePath = defaultStoragePath()
+	}
+
+	totalMemory, availableMemory, err := readHostMemory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host memory: %w", err)
+	}
+
+	// The storage directory is often only readable by the daemon, so measure
+	// the closest ancestor we can access, which lives on the same disk
+	diskPath := storagePath
+	totalDisk, availableDisk, err := readDiskSpace(diskPath)
+	for err != nil && filepath.Dir(diskPath) != diskPath {
+		diskPath = filepath.Dir(diskPath)
+		totalDisk, availableDisk, err = readDiskSpace(diskPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read free disk space for %s: %w", storagePath, err)
+	}
+
+	return &HostCapacity{
+		CPUs:            int64(runtime.NumCPU()),
+		TotalMemory:     totalMemory,
+		AvailableMemory: availableMemory,
+		TotalDisk:       totalDisk,
+		AvailableDisk:   availableDisk,
+		StoragePath:     storagePath,
+	}, nil
+}
+
+// instanceAllocation is the CPU, memory and disk planned for one instance.
+// A negative value means the planned value is not known yet.
+type instanceAllocation struct {
+	CPUs   int64
+	Memory int64
+	Disk   int64
+}
+
+// capacityUsage reports an allocation total that exceeds the host
+type capacityUsage struct {
+	Resource  string
+	Planned   int64
+	Available int64
+	Ratio     float64
+}
+
+// capacityPlanner sums the allocations of all instances planned for creation
+// by this provider instance and compares them with the host
+type capacityPlanner struct {
+	config CapacityCheckConfig
+
+	mu      sync.Mutex
+	host    *HostCapacity
+	hostErr error
+	loaded  bool
+	planned map[string]instanceAllocation
+}
+
+// newCapacityPlanner creates a planner with the given settings
+func newCapacityPlanner(config CapacityCheckConfig) *capacityPlanner {
+	return &capacityPlanner{
+		config:  config,
+		planned: map[string]instanceAllocation{},
+	}
+}
+
+// Host returns the host capacity, reading it once per provider instance
+func (p *capacityPlanner) Host() (*HostCapacity, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.hostLocked()
+}
+
+func (p *capacityPlanner) hostLocked() (*HostCapacity, error) {
+	if !p.loaded {
+		p.host, p.hostErr = readHostCapacity(p.config.StoragePath)
+		p.loaded = true
+	}
+	return p.host, p.hostErr
+}
+
+// Plan records the allocation planned for the named instance and returns the
+// totals across all planned instances that exceed the host capacity. An
+// instance without a name yet is counted separately on every call.
+func (p *capacityPlanner) Plan(name string, allocation instanceAllocation) ([]capacityUsage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	host, err := p.hostLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		// Instance names cannot contain '#', so this never matches a real one
+		name = fmt.Sprintf("#%d", len(p.planned))
+	}
+	p.planned[name] = allocation
+
+	var total instanceAllocation
+	for _, planned := range p.planned {
+		total.CPUs += max(planned.CPUs, 0)
+		total.Memory += max(planned.Memory, 0)
+		total.Disk += max(planned.Disk, 0)
+	}
+
+	return p.config.exceeded(host, total), nil
+}
+
+// exceeded compares planned totals with the host capacity scaled by the overcommit ratios
+func (c CapacityCheckConfig) exceeded(host *HostCapacity, total instanceAllocation) []capacityUsage {
+	checks := []capacityUsage{
+		{Resource: "cpu", Planned: total.CPUs, Available: host.CPUs, Ratio: c.CPUOvercommitRatio},
+		{Resource: "memory", Planned: total.Memory, Available: host.AvailableMemory, Ratio: c.MemoryOvercommitRatio},
+		{Resource: "disk", Planned: total.Disk, Available: host.AvailableDisk, Ratio: c.DiskOvercommitRatio},
+	}
+
+	var exceeded []capacityUsage
+	for _, check := range checks {
+		if float64(check.Planned) > float64(check.Available)*check.Ratio {
+			exceeded = append(exceeded, check)
+		}
+	}
+	return exceeded
+}
+
+// String describes the overcommitted resource for diagnostics
+func (u capacityUsage) String() string {
+	planned, available := fmt.Sprint(u.Planned), fmt.Sprint(u.Available)
+	if u.Resource != "cpu" {
+		planned, available = formatBytes(u.Planned), formatBytes(u.Available)
+	}
+	return fmt.Sprintf("planned instances request %s of %s, but the host has %s available (overcommit ratio %g)",
+		planned, u.Resource, available, u.Ratio)
+}
+
+// formatBytes renders a byte count in binary units for messages
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// reclaimablePageCounts are the sysctls counting pages macOS hands out on
+// demand: speculative read-ahead, file cache and purgeable memory. Free pages
+// alone stay near zero on a busy Mac.
+var reclaimablePageCounts = []string{
+	"vm.page_speculative_count",
+	"vm.page_pageable_external_count",
+	"vm.page_purgeable_count",
+}
+
+// readHostMemory returns total and available memory from sysctl
+func readHostMemory() (int64, int64, error) {
+	total, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	freePages, err := unix.SysctlUint32("vm.page_free_count")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	availablePages := int64(freePages)
+	for _, name := range reclaimablePageCounts {
+		// Older releases lack some counters, which then count as none
+		if pages, err := unix.SysctlUint32(name); err == nil {
+			availablePages += int64(pages)
+		}
+	}
+
+	return int64(total), min(availablePages*int64(unix.Getpagesize()), int64(total)), nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package provider
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// readDiskSpace is not implemented on this platform
+func readDiskSpace(path string) (int64, int64, error) {
+	return 0, 0, fmt.Errorf("reading disk space is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package provider
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// readHostMemory is not implemented on this platform
+func readHostMemory() (int64, int64, error) {
+	return 0, 0, fmt.Errorf("reading host memory is not supported on %s", runtime.GOOS)
+}
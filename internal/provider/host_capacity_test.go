@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReadHostCapacity(t *testing.T) {
+	// A missing storage directory is measured through its closest existing parent
+	host, err := readHostCapacity(t.TempDir() + "/does/not/exist")
+	if err != nil {
+		t.Skipf("host capacity not supported here: %v", err)
+	}
+
+	if host.CPUs < 1 {
+		t.Errorf("expected at least one CPU, got %d", host.CPUs)
+	}
+	if host.TotalMemory <= 0 || host.AvailableMemory <= 0 || host.AvailableMemory > host.TotalMemory {
+		t.Errorf("unexpected memory total=%d available=%d", host.TotalMemory, host.AvailableMemory)
+	}
+	if host.TotalDisk <= 0 || host.AvailableDisk > host.TotalDisk {
+		t.Errorf("unexpected disk total=%d available=%d", host.TotalDisk, host.AvailableDisk)
+	}
+}
+
+func TestCapacityCheckConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*CapacityCheckConfig)
+		wantErr bool
+	}{
+		{"Default", func(c *CapacityCheckConfig) {}, false},
+		{"Error mode", func(c *CapacityCheckConfig) { c.Mode = capacityCheckError }, false},
+		{"Off mode", func(c *CapacityCheckConfig) { c.Mode = capacityCheckOff }, false},
+		{"Unknown mode", func(c *CapacityCheckConfig) { c.Mode = "fail" }, true},
+		{"Zero ratio", func(c *CapacityCheckConfig) { c.MemoryOvercommitRatio = 0 }, true},
+		{"Overcommit", func(c *CapacityCheckConfig) { c.CPUOvercommitRatio = 4 }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultCapacityCheckConfig()
+			tt.modify(&config)
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCapacityPlanner(t *testing.T) {
+	planner := newCapacityPlanner(DefaultCapacityCheckConfig())
+	planner.host = &HostCapacity{CPUs: 4, AvailableMemory: 8 << 30, AvailableDisk: 100 << 30}
+	planner.loaded = true
+
+	exceeded, err := planner.Plan("one", instanceAllocation{CPUs: 2, Memory: 4 << 30, Disk: 50 << 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exceeded) != 0 {
+		t.Fatalf("expected first instance to fit, got %v", exceeded)
+	}
+
+	// Planning the same instance again must not count it twice
+	if exceeded, _ = planner.Plan("one", instanceAllocation{CPUs: 2, Memory: 4 << 30, Disk: 50 << 30}); len(exceeded) != 0 {
+		t.Fatalf("expected re-planned instance to fit, got %v", exceeded)
+	}
+
+	exceeded, _ = planner.Plan("two", instanceAllocation{CPUs: 2, Memory: 6 << 30, Disk: -1})
+	if len(exceeded) != 1 || exceeded[0].Resource != "memory" {
+		t.Fatalf("expected memory to be exceeded, got %v", exceeded)
+	}
+
+	planner.config.MemoryOvercommitRatio = 1.5
+	if exceeded, _ = planner.Plan("two", instanceAllocation{CPUs: 2, Memory: 6 << 30, Disk: -1}); len(exceeded) != 0 {
+		t.Fatalf("expected overcommit ratio to allow memory, got %v", exceeded)
+	}
+
+	// Instances named at apply time are each counted
+	if exceeded, _ = planner.Plan("", instanceAllocation{Memory: 3 << 29}); len(exceeded) != 0 {
+		t.Fatalf("expected unnamed instance to fit, got %v", exceeded)
+	}
+	if exceeded, _ = planner.Plan("", instanceAllocation{Memory: 3 << 29}); len(exceeded) != 1 || exceeded[0].Resource != "memory" {
+		t.Fatalf("expected unnamed instances to be added up, got %v", exceeded)
+	}
+}
+
+func TestPlannedAllocation(t *testing.T) {
+	allocation := plannedAllocation(InstanceResourceModel{
+		CPU:    types.StringNull(),
+		Memory: types.StringValue("2G"),
+		Disk:   types.StringUnknown(),
+	})
+
+	want := instanceAllocation{CPUs: multipassDefaultCPUs, Memory: 2 << 30, Disk: -1}
+	if allocation != want {
+		t.Errorf("plannedAllocation() = %+v, want %+v", allocation, want)
+	}
+}
+
+func TestCapacityUsageString(t *testing.T) {
+	usage := capacityUsage{Resource: "memory", Planned: 3 << 30, Available: 2 << 30, Ratio: 1}
+	want := "planned instances request 3.0G of memory, but the host has 2.0G available (overcommit ratio 1)"
+	if got := usage.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd
+
+package provider
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// readDiskSpace returns the total and available bytes of the filesystem holding path
+func readDiskSpace(path string) (int64, int64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := int64(stat.Bsize)
+	return int64(stat.Blocks) * blockSize, int64(stat.Bavail) * blockSize, nil
+}
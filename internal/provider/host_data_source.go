@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &HostDataSource{}
+
+func NewHostDataSource() datasource.DataSource {
+	return &HostDataSource{}
+}
+
+// HostDataSource defines the data source implementation.
+type HostDataSource struct {
+	client *MultipassClient
+}
+
+// HostDataSourceModel describes the data source data model.
+type HostDataSourceModel struct {
+	Id              types.String `tfsdk:"id"`
+	StoragePath     types.String `tfsdk:"storage_path"`
+	CPUCount        types.Int64  `tfsdk:"cpu_count"`
+	MemoryTotal     types.Int64  `tfsdk:"memory_total"`
+	MemoryAvailable types.Int64  `tfsdk:"memory_available"`
+	DiskTotal       types.Int64  `tfsdk:"disk_total"`
+	DiskAvailable   types.Int64  `tfsdk:"disk_available"`
+}
+
+func (d *HostDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host"
+}
+
+func (d *HostDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Multipass host data source. Reports the CPUs, memory and free disk space of the machine running multipass, e.g. to size instances to fit the host.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier",
+				Computed:            true,
+			},
+			"storage_path": schema.StringAttribute{
+				MarkdownDescription: "Directory used to measure disk space. Defaults to `storage_path` of the provider `capacity_check` block, then to the platform location of the multipass daemon data.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cpu_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of logical CPUs",
+				Computed:            true,
+			},
+			"memory_total": schema.Int64Attribute{
+				MarkdownDescription: "Total memory in bytes",
+				Computed:            true,
+			},
+			"memory_available": schema.Int64Attribute{
+				MarkdownDescription: "Memory available for new instances in bytes",
+				Computed:            true,
+			},
+			"disk_total": schema.Int64Attribute{
+				MarkdownDescription: "Size of the filesystem holding `storage_path` in bytes",
+				Computed:            true,
+			},
+			"disk_available": schema.Int64Attribute{
+				MarkdownDescription: "Free space on the filesystem holding `storage_path` in bytes",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *HostDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*MultipassClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *MultipassClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *HostDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data HostDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storagePath := data.StoragePath.ValueString()
+	if storagePath == "" && d.client != nil {
+		storagePath = d.client.CapacityCheck().StoragePath
+	}
+
+	tflog.Trace(ctx, "reading multipass host capacity", map[string]interface{}{
+		"storage_path": storagePath,
+	})
+
+	host, err := readHostCapacity(storagePath)
+	if err != nil {
+		resp.Diagnostics.AddError("Host Error", fmt.Sprintf("Unable to read host capacity, got error: %s", err))
+		return
+	}
+
+	data.Id = types.StringValue(host.StoragePath)
+	data.StoragePath = types.StringValue(host.StoragePath)
+	data.CPUCount = types.Int64Value(host.CPUs)
+	data.MemoryTotal = types.Int64Value(host.TotalMemory)
+	data.MemoryAvailable = types.Int64Value(host.AvailableMemory)
+	data.DiskTotal = types.Int64Value(host.TotalDisk)
+	data.DiskAvailable = types.Int64Value(host.AvailableDisk)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHostDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHostDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.multipass_host.test", "storage_path"),
+					resource.TestCheckResourceAttrSet("data.multipass_host.test", "cpu_count"),
+					resource.TestCheckResourceAttrSet("data.multipass_host.test", "memory_available"),
+					resource.TestCheckResourceAttrSet("data.multipass_host.test", "disk_available"),
+				),
+			},
+		},
+	})
+}
+
+const testAccHostDataSourceConfig = `
+data "multipass_host" "test" {}
+`
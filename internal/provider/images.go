@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// defaultImageRemote is the remote multipass uses for images without a
+// remote prefix
+const defaultImageRemote = "release"
+
+// FindImages runs `multipass find` and returns the images and blueprints of
+// a remote, or of the default remote when remote is empty
+func (c *MultipassClient) FindImages(ctx context.Context, remote string) (*common.MultipassFindResult, error) {
+	args := []string{"find"}
+	if remote != "" {
+		args = append(args, remote+":")
+	}
+	args = append(args, "--format", "json")
+
+	result, err := c.runWithRetry(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find images: %w, output: %s", err, string(result.Stderr))
+	}
+
+	var found common.MultipassFindResult
+	if err := json.Unmarshal(result.Stdout, &found); err != nil {
+		return nil, fmt.Errorf("failed to parse image list: %w", err)
+	}
+
+	return &found, nil
+}
+
+// hasImage reports whether an image is listed for a remote, either by name
+// or by one of its aliases
+func hasImage(found *common.MultipassFindResult, remote string, image string) bool {
+	if remote == "" {
+		remote = defaultImageRemote
+	}
+
+	for key, candidate := range found.Images {
+		name := key
+		imageRemote := candidate.Remote
+		if prefix, rest, ok := strings.Cut(key, ":"); ok {
+			imageRemote, name = prefix, rest
+		}
+		if imageRemote == "" {
+			imageRemote = defaultImageRemote
+		}
+		if imageRemote != remote {
+			continue
+		}
+
+		if name == image {
+			return true
+		}
+		for _, alias := range candidate.Aliases {
+			if alias == image {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasBlueprint reports whether a blueprint is listed
+func hasBlueprint(found *common.MultipassFindResult, blueprint string) bool {
+	if _, ok := found.Blueprints[blueprint]; ok {
+		return true
+	}
+	_, ok := found.DeprecatedBlueprints[blueprint]
+	return ok
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeFindBinary creates a fake multipass binary listing images of the
+// release and daily remotes and the docker blueprint
+func writeFindBinary(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, `if [ "$1" = "find" ] && [ "$2" = "daily:" ]; then
+  echo '{"errors": [], "images": {"daily:24.04": {"aliases": ["noble"], "os": "Ubuntu", "release": "24.04 LTS", "remote": "daily"}}}'
+  exit 0
+fi
+if [ "$1" = "find" ]; then
+  echo '{"errors": [], "blueprints (deprecated)": {"docker": {"aliases": [], "os": "", "release": "", "remote": ""}}, "images": {"22.04": {"aliases": ["jammy"], "os": "Ubuntu", "release": "22.04 LTS", "remote": ""}, "24.04": {"aliases": ["noble", "lts"], "os": "Ubuntu", "release": "24.04 LTS", "remote": ""}}}'
+  exit 0
+fi
+exit 0
+`)
+}
+
+func TestFindImages(t *testing.T) {
+	binary, calls := writeFindBinary(t)
+	client := NewMultipassClient(binary)
+	ctx := context.Background()
+
+	found, err := client.FindImages(ctx, "")
+	if err != nil {
+		t.Fatalf("FindImages() error = %v", err)
+	}
+
+	for _, image := range []string{"22.04", "jammy", "lts"} {
+		if !hasImage(found, "", image) {
+			t.Errorf("Expected image %q to be found", image)
+		}
+	}
+	if hasImage(found, "", "18.04") || hasImage(found, "daily", "24.04") {
+		t.Error("Expected unlisted images not to be found")
+	}
+	if !hasBlueprint(found, "docker") || hasBlueprint(found, "minikube") {
+		t.Error("Expected only the docker blueprint to be found")
+	}
+
+	daily, err := client.FindImages(ctx, "daily")
+	if err != nil {
+		t.Fatalf("FindImages(daily) error = %v", err)
+	}
+	if !hasImage(daily, "daily", "24.04") || !hasImage(daily, "daily", "noble") {
+		t.Error("Expected daily:24.04 to be found by name and alias")
+	}
+
+	got := readCalls(t, calls)
+	if len(got) != 2 || got[0] != "find --format json" || got[1] != "find daily: --format json" {
+		t.Errorf("Unexpected calls %q", got)
+	}
+}
+
+func TestCheckImageAvailable(t *testing.T) {
+	binary, _ := writeFindBinary(t)
+	r := &InstanceResource{client: NewMultipassClient(binary)}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name      string
+		image     string
+		remote    string
+		blueprint string
+		wantErr   string
+	}{
+		{name: "Release image", image: "lts"},
+		{name: "Daily image", image: "24.04", remote: "daily"},
+		{name: "Blueprint", blueprint: "docker"},
+		{name: "URL image", image: "https://example.com/custom.img"},
+		{name: "Missing image", image: "18.04", wantErr: "Image Not Found"},
+		{name: "Missing daily image", image: "22.04", remote: "daily", wantErr: "`multipass find daily:`"},
+		{name: "Missing blueprint", blueprint: "minikube", wantErr: "Blueprint Not Found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := testInstanceModel("web")
+			plan.Image = stringOrNull(tc.image)
+			plan.ImageRemote = stringOrNull(tc.remote)
+			plan.Blueprint = stringOrNull(tc.blueprint)
+
+			diags := r.checkImageAvailable(ctx, plan)
+			if tc.wantErr == "" {
+				if diags.HasError() {
+					t.Errorf("Unexpected errors: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatal("Expected an error, got none")
+			}
+			if err := diags.Errors()[0]; !strings.Contains(err.Summary()+" "+err.Detail(), tc.wantErr) {
+				t.Errorf("Expected error to contain %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckImageAvailableFindFailure(t *testing.T) {
+	r := &InstanceResource{client: NewMultipassClient("/nonexistent/multipass")}
+
+	plan := testInstanceModel("web")
+	diags := r.checkImageAvailable(context.Background(), plan)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("Expected a single warning when multipass find fails, got %v", diags)
+	}
+}
+
+// TestModifyPlanImageRemoteDefaults tests that image_remote accepts an image
+// set only through the provider defaults
+func TestModifyPlanImageRemoteDefaults(t *testing.T) {
+	client := NewMultipassClient("/nonexistent/multipass")
+	client.SetCapacityCheck(CapacityCheckConfig{Mode: capacityCheckOff})
+	r := &InstanceResource{client: client}
+
+	config := testInstanceModel("web")
+	config.Id = types.StringNull()
+	config.Image = types.StringNull()
+	config.ImageRemote = types.StringValue("daily")
+	config.State = types.StringNull()
+	config.IPv4 = types.ListNull(types.StringType)
+
+	planned := config
+	planned.Id = types.StringUnknown()
+	planned.Image = types.StringUnknown()
+	planned.State = types.StringUnknown()
+	planned.IPv4 = types.ListUnknown(types.StringType)
+
+	// Without a default image there is nothing to take from the remote
+	resp := modifyInstancePlanWithConfig(t, r, nil, &planned, &config)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "image_remote requires image") {
+		t.Fatalf("Expected an image_remote error, got %v", resp.Diagnostics)
+	}
+
+	client.SetInstanceDefaults(common.InstanceDefaults{Image: "24.04"})
+	resp = modifyInstancePlanWithConfig(t, r, nil, &planned, &config)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected errors: %v", resp.Diagnostics)
+	}
+
+	var image types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("image"), &image)...)
+	if image.ValueString() != "24.04" {
+		t.Errorf("Expected the default image to be planned, got %s", image)
+	}
+}
+
+func TestLaunchImageArguments(t *testing.T) {
+	binary, calls := writeFindBinary(t)
+	client := NewMultipassClient(binary)
+	ctx := context.Background()
+
+	if err := client.Launch(ctx, &common.LaunchOptions{Name: "web", Image: "24.04", Remote: "daily"}); err != nil {
+		t.Fatalf("Launch() error = %v", err)
+	}
+	if err := client.Launch(ctx, &common.LaunchOptions{Name: "dev", Blueprint: "docker"}); err != nil {
+		t.Fatalf("Launch() error = %v", err)
+	}
+
+	got := readCalls(t, calls)
+	if len(got) != 2 || got[0] != "launch daily:24.04 --name web" || got[1] != "launch docker --name dev" {
+		t.Errorf("Unexpected calls %q", got)
+	}
+
+	if err := client.Launch(ctx, &common.LaunchOptions{Name: "web", Image: "22.04", Blueprint: "docker"}); err == nil {
+		t.Error("Expected a blueprint combined with an image to be rejected")
+	}
+	if err := client.Launch(ctx, &common.LaunchOptions{Name: "web", Remote: "daily"}); err == nil {
+		t.Error("Expected a remote without an image to be rejected")
+	}
+}
+
+func TestRecordImageDetails(t *testing.T) {
+	data := testInstanceModel("web")
+	data.Image = types.StringValue("lts")
+	data.Release = types.StringUnknown()
+	data.ImageHash = types.StringUnknown()
+
+	recordImageDetails(&data, &common.MultipassInstance{Release: "Ubuntu 24.04 LTS", ImageHash: "abc123"})
+	if data.Release.ValueString() != "Ubuntu 24.04 LTS" || data.ImageHash.ValueString() != "abc123" {
+		t.Errorf("Expected the launched release and hash, got %s and %s", data.Release, data.ImageHash)
+	}
+
+	// Once lts moves on, the recorded values stay
+	recordImageDetails(&data, &common.MultipassInstance{Release: "Ubuntu 26.04 LTS", ImageHash: "def456"})
+	if data.Release.ValueString() != "Ubuntu 24.04 LTS" || data.ImageHash.ValueString() != "abc123" {
+		t.Errorf("Expected the recorded release and hash to be kept, got %s and %s", data.Release, data.ImageHash)
+	}
+}
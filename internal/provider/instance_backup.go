@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ways of keeping a copy of an instance before it is destroyed
+const (
+	backupMethodClone    = "clone"
+	backupMethodSnapshot = "snapshot"
+)
+
+// backupMethods lists the accepted snapshot_before_destroy methods
+var backupMethods = []string{backupMethodClone, backupMethodSnapshot}
+
+// defaultBackupRetain is how many backups of an instance are kept when
+// snapshot_before_destroy does not set retain
+const defaultBackupRetain = 3
+
+// backupTimestampFormat makes backup names sort in creation order and keeps
+// them valid instance names
+const backupTimestampFormat = "20060102-150405"
+
+// backupCloneSuffix separates an instance name from the timestamp of its clones
+const backupCloneSuffix = "-backup-"
+
+// SnapshotBeforeDestroyModel describes the snapshot_before_destroy block.
+type SnapshotBeforeDestroyModel struct {
+	Method types.String `tfsdk:"method"`
+	Retain types.Int64  `tfsdk:"retain"`
+}
+
+// snapshotBeforeDestroyBlock returns the schema of the snapshot_before_destroy block
+func snapshotBeforeDestroyBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Keep a copy of the instance whenever it is destroyed or replaced. The instance is stopped first.",
+		Attributes: map[string]schema.Attribute{
+			"method": schema.StringAttribute{
+				MarkdownDescription: "`clone` copies the instance to `<name>-backup-<timestamp>`, shortening names longer than 40 characters (multipass 1.15+). " +
+					"`snapshot` takes a `pre-destroy-<timestamp>` snapshot and deletes the instance without purging it, " +
+					"so it can be brought back with `multipass recover` and `multipass restore` (multipass 1.13+); " +
+					"the name stays taken until the instance is purged, so plans replacing the instance under the same name are rejected. Defaults to `clone`.",
+				Optional: true,
+				Validators: []validator.String{
+					oneOfValidator("backup method", backupMethods...),
+				},
+			},
+			"retain": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of backups of this instance to keep, older ones are deleted. Defaults to %d.", defaultBackupRetain),
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// backupMethod returns the configured method, or the default
+func (m *SnapshotBeforeDestroyModel) backupMethod() string {
+	if m.Method.IsNull() || m.Method.IsUnknown() {
+		return backupMethodClone
+	}
+	return m.Method.ValueString()
+}
+
+// retain returns the configured number of backups to keep, or the default
+func (m *SnapshotBeforeDestroyModel) retain() int {
+	if m.Retain.IsNull() || m.Retain.IsUnknown() {
+		return defaultBackupRetain
+	}
+	return int(m.Retain.ValueInt64())
+}
+
+// backupCapability returns the multipass feature the backup method relies on
+func backupCapability(method string) Capability {
+	if method == backupMethodSnapshot {
+		return CapabilitySnapshots
+	}
+	return CapabilityClone
+}
+
+// checkBackupCapability reports when the installed multipass cannot take the
+// configured backup
+func (r *InstanceResource) checkBackupCapability(plan InstanceResourceModel) diag.Diagnostics {
+	if plan.SnapshotBeforeDestroy == nil || r.client == nil {
+		return nil
+	}
+
+	method := plan.SnapshotBeforeDestroy.backupMethod()
+	return r.client.requireCapability(backupCapability(method), path.Root("snapshot_before_destroy").AtName("method"))
+}
+
+// backupBeforeDelete keeps a copy of a stopped instance as configured by
+// snapshot_before_destroy, then prunes backups beyond the retention count.
+// It reports whether the instance may be purged when it is deleted.
+func (r *InstanceResource) backupBeforeDelete(ctx context.Context, name string, config *SnapshotBeforeDestroyModel) (bool, error) {
+	method := config.backupMethod()
+	timestamp := time.Now().UTC().Format(backupTimestampFormat)
+
+	if method == backupMethodSnapshot {
+		snapshot := "pre-destroy-" + timestamp
+		tflog.Info(ctx, "taking multipass snapshot before destroy", map[string]interface{}{"name": name, "snapshot": snapshot})
+
+		if err := r.client.CreateSnapshot(ctx, name, snapshot); err != nil {
+			return false, err
+		}
+		if err := r.pruneSnapshots(ctx, name, config.retain()); err != nil {
+			return false, err
+		}
+
+		// Purging would delete the snapshots along with the instance
+		return false, nil
+	}
+
+	clone := backupClonePrefix(name) + timestamp
+	if err := validateInstanceName(clone); err != nil {
+		return false, fmt.Errorf("unable to name the backup of instance %s: %w", name, err)
+	}
+
+	tflog.Info(ctx, "cloning multipass instance before destroy", map[string]interface{}{"name": name, "clone": clone})
+
+	if err := r.client.CloneInstance(ctx, name, clone); err != nil {
+		return false, err
+	}
+	if err := r.pruneClones(ctx, name, config.retain()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// pruneSnapshots deletes all but the newest retain pre-destroy snapshots of an instance
+func (r *InstanceResource) pruneSnapshots(ctx context.Context, name string, retain int) error {
+	snapshots, err := r.client.ListSnapshots(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range expiredBackups(snapshots, "pre-destroy-", retain) {
+		tflog.Info(ctx, "deleting expired multipass snapshot", map[string]interface{}{"name": name, "snapshot": snapshot})
+		if err := r.client.DeleteSnapshot(ctx, name, snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneClones deletes all but the newest retain backup clones of an instance
+func (r *InstanceResource) pruneClones(ctx context.Context, name string, retain int) error {
+	instances, err := r.client.ListInstances(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		names = append(names, instance.Name)
+	}
+
+	for _, clone := range expiredBackups(names, backupClonePrefix(name), retain) {
+		tflog.Info(ctx, "deleting expired multipass backup clone", map[string]interface{}{"name": name, "clone": clone})
+		if err := r.client.DeleteInstance(ctx, clone, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backupClonePrefix returns the name of the backup clones of an instance
+// without their timestamp. Long names are shortened and followed by a hash
+// of the full name, keeping clones within the instance name limit and apart
+// from the clones of other instances sharing the shortened name.
+func backupClonePrefix(name string) string {
+	prefix := name + backupCloneSuffix
+	if len(prefix)+len(backupTimestampFormat) <= maxInstanceNameLength {
+		return prefix
+	}
+
+	hash := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(name)))
+	keep := maxInstanceNameLength - len(backupTimestampFormat) - len(backupCloneSuffix) - len(hash) - 1
+	return strings.TrimRight(name[:keep], "-") + "-" + hash + backupCloneSuffix
+}
+
+// expiredBackups returns the names starting with prefix that are older than
+// the newest retain ones. The timestamp suffix makes names sort by age.
+func expiredBackups(names []string, prefix string, retain int) []string {
+	var backups []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			backups = append(backups, name)
+		}
+	}
+
+	if len(backups) <= retain {
+		return nil
+	}
+
+	sort.Strings(backups)
+	return backups[:len(backups)-retain]
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeBackupBinary creates a fake multipass binary listing two older backup
+// clones and two older pre-destroy snapshots of instance "web"
+func writeBackupBinary(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, `if [ "$1" = "list" ] && [ "$2" = "--snapshots" ]; then
+  echo '{"errors": [], "info": {"web": {"pre-destroy-20250101-000000": {}, "pre-destroy-20250201-000000": {}, "manual": {}}, "db": {"pre-destroy-20240101-000000": {}}}}'
+  exit 0
+fi
+if [ "$1" = "list" ]; then
+  echo '{"list": [{"name": "web", "state": "Stopped"}, {"name": "web-backup-20250101-000000", "state": "Stopped"}, {"name": "web-backup-20250201-000000", "state": "Stopped"}, {"name": "db-backup-20240101-000000", "state": "Stopped"}]}'
+  exit 0
+fi
+exit 0
+`)
+}
+
+// newBackupClient returns a client for a multipass release supporting clones
+func newBackupClient(binary string) *MultipassClient {
+	client := NewMultipassClient(binary)
+	client.version = &common.MultipassVersion{Client: "1.15.0", Daemon: "1.15.0"}
+	return client
+}
+
+func TestBackupBeforeDeleteClone(t *testing.T) {
+	binary, calls := writeBackupBinary(t)
+	r := &InstanceResource{client: newBackupClient(binary)}
+
+	purge, err := r.backupBeforeDelete(context.Background(), "web", &SnapshotBeforeDestroyModel{
+		Method: types.StringNull(),
+		Retain: types.Int64Value(1),
+	})
+	if err != nil {
+		t.Fatalf("backupBeforeDelete() error = %v", err)
+	}
+	if !purge {
+		t.Error("Expected a cloned instance to be purged")
+	}
+
+	// The fake binary does not list the new clone, so with retain = 1 only
+	// the newest listed one is kept
+	got := readCalls(t, calls)
+	if len(got) != 3 || !strings.HasPrefix(got[0], "clone web --name web-backup-") ||
+		got[1] != "list --format json" || got[2] != "delete --purge web-backup-20250101-000000" {
+		t.Errorf("Unexpected calls %q", got)
+	}
+}
+
+func TestBackupBeforeDeleteSnapshot(t *testing.T) {
+	binary, calls := writeBackupBinary(t)
+	r := &InstanceResource{client: newBackupClient(binary)}
+
+	purge, err := r.backupBeforeDelete(context.Background(), "web", &SnapshotBeforeDestroyModel{
+		Method: types.StringValue("snapshot"),
+		Retain: types.Int64Value(1),
+	})
+	if err != nil {
+		t.Fatalf("backupBeforeDelete() error = %v", err)
+	}
+	if purge {
+		t.Error("Expected an instance with a snapshot not to be purged")
+	}
+
+	got := readCalls(t, calls)
+	if len(got) != 3 || !strings.HasPrefix(got[0], "snapshot web --name pre-destroy-") ||
+		got[1] != "list --snapshots --format json" {
+		t.Fatalf("Unexpected calls %q", got)
+	}
+
+	// The fake binary does not list the new snapshot, so with retain = 1
+	// only the newest listed one is kept
+	if got[2] != "delete --purge web.pre-destroy-20250101-000000" {
+		t.Errorf("Expected the oldest snapshot to be deleted, got %q", got[2])
+	}
+}
+
+func TestExpiredBackups(t *testing.T) {
+	names := []string{"web-backup-3", "web", "web-backup-1", "db-backup-0", "web-backup-2"}
+
+	if got, want := expiredBackups(names, "web-backup-", 1), []string{"web-backup-1", "web-backup-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expiredBackups(retain 1) = %q, want %q", got, want)
+	}
+	if got := expiredBackups(names, "web-backup-", 3); got != nil {
+		t.Errorf("expiredBackups(retain 3) = %q, want none", got)
+	}
+}
+
+func TestBackupClonePrefix(t *testing.T) {
+	if got := backupClonePrefix("web"); got != "web-backup-" {
+		t.Errorf("backupClonePrefix(web) = %q, want web-backup-", got)
+	}
+
+	long := "a-very-long-instance-name-for-the-reporting-stack"
+	prefix := backupClonePrefix(long)
+	clone := prefix + time.Now().UTC().Format(backupTimestampFormat)
+	if err := validateInstanceName(clone); err != nil {
+		t.Errorf("Expected a valid clone name for a long instance name, got %q: %v", clone, err)
+	}
+	if prefix == backupClonePrefix(long+"-2") {
+		t.Errorf("Expected shortened names of different instances to differ, got %q", prefix)
+	}
+}
+
+func TestModifyPlanSnapshotReplacement(t *testing.T) {
+	testCases := []struct {
+		name    string
+		method  string
+		rename  bool
+		wantErr bool
+	}{
+		{name: "Snapshot", method: backupMethodSnapshot, wantErr: true},
+		{name: "Snapshot renamed", method: backupMethodSnapshot, rename: true},
+		{name: "Clone", method: backupMethodClone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prior := testInstanceModel("web")
+			prior.SnapshotBeforeDestroy = &SnapshotBeforeDestroyModel{
+				Method: types.StringValue(tc.method),
+				Retain: types.Int64Null(),
+			}
+
+			planned := prior
+			planned.Memory = types.StringValue("4G")
+			if tc.rename {
+				planned.Name = types.StringValue("web-2")
+			}
+
+			diags := runInstanceModifyPlan(t, &prior, &planned)
+			if tc.wantErr {
+				if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Instance Replacement Not Possible" {
+					t.Errorf("Expected the replacement to be rejected, got %v", diags)
+				}
+				return
+			}
+			if diags.HasError() || diags.WarningsCount() != 1 {
+				t.Errorf("Expected only a replacement warning, got %v", diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// defaultReadCacheTTL is how long a batched `multipass info --all` result is
+// reused for instance reads when the provider does not configure it
+const defaultReadCacheTTL = 10 * time.Second
+
+// instanceCache keeps a short-lived snapshot of all instances so that
+// refreshing many resources costs a single multipass invocation
+type instanceCache struct {
+	ttl time.Duration
+
+	// mu is held while the snapshot is fetched, so concurrent reads wait for
+	// the in-flight call instead of starting their own
+	mu        sync.Mutex
+	instances map[string]common.MultipassInstance
+	fetchedAt time.Time
+}
+
+// newInstanceCache creates a cache with the given TTL, or nil when ttl is not positive
+func newInstanceCache(ttl time.Duration) *instanceCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &instanceCache{ttl: ttl}
+}
+
+// lookup returns the named instance from the snapshot, refreshing it with
+// fetch when it is missing or expired. The boolean reports whether the
+// instance is present in the snapshot.
+func (c *instanceCache) lookup(ctx context.Context, name string, fetch func(context.Context) (map[string]common.MultipassInstance, error)) (*common.MultipassInstance, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.instances == nil || time.Since(c.fetchedAt) > c.ttl {
+		instances, err := fetch(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		c.instances = instances
+		c.fetchedAt = time.Now()
+	}
+
+	instance, exists := c.instances[name]
+	if !exists {
+		return nil, false, nil
+	}
+	return &instance, true, nil
+}
+
+// invalidate drops the snapshot so the next lookup fetches fresh data
+func (c *instanceCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.instances = nil
+}
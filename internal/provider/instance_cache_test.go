@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeInfoBinary creates a fake multipass that answers `info --all` with two
+// instances, logs every invocation and fails any other info call
+func writeInfoBinary(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, `if [ "$1" = "info" ] && [ "$2" = "--all" ]; then
+  echo '{"errors": [], "info": {"web-1": {"state": "Running", "ipv4": ["10.0.0.2"]}, "web-2": {"state": "Stopped"}}}'
+  exit 0
+fi
+if [ "$1" = "info" ]; then
+  echo "info failed" >&2
+  exit 2
+fi
+exit 0
+`)
+}
+
+// writeFakeMultipass creates a fake multipass binary that logs every
+// invocation to the calls file, which body can read as "$calls", and then
+// runs the shell commands in body
+func writeFakeMultipass(t *testing.T, body string) (binary, calls string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	calls = filepath.Join(tempDir, "calls")
+	script := fmt.Sprintf("#!/bin/sh\ncalls='%s'\necho \"$*\" >> \"$calls\"\n%s", calls, body)
+
+	binary = filepath.Join(tempDir, "multipass")
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake binary: %v", err)
+	}
+
+	return binary, calls
+}
+
+func readCalls(t *testing.T, calls string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("Failed to read calls: %v", err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+// TestGetInstanceBatchesReads tests that reads share one `info --all` call
+func TestGetInstanceBatchesReads(t *testing.T) {
+	binary, calls := writeInfoBinary(t)
+	client := NewMultipassClient(binary)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := client.GetInstance(context.Background(), name); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}([]string{"web-1", "web-2"}[i%2])
+	}
+	wg.Wait()
+
+	instance, err := client.GetInstance(context.Background(), "web-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instance.Name != "web-1" || instance.State != "Running" {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+
+	if got := readCalls(t, calls); len(got) != 1 || got[0] != "info --all --format json" {
+		t.Errorf("Expected a single batched info call, got %v", got)
+	}
+}
+
+// TestGetInstanceCacheNotFound tests that missing instances are reported as not found
+func TestGetInstanceCacheNotFound(t *testing.T) {
+	binary, _ := writeInfoBinary(t)
+	client := NewMultipassClient(binary)
+
+	_, err := client.GetInstance(context.Background(), "db-1")
+	if !errors.Is(err, ErrInstanceNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+// TestGetInstanceCacheInvalidation tests that mutating calls drop the cache
+func TestGetInstanceCacheInvalidation(t *testing.T) {
+	binary, calls := writeInfoBinary(t)
+	client := NewMultipassClient(binary)
+
+	if _, err := client.GetInstance(context.Background(), "web-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.StopInstance(context.Background(), "web-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.GetInstance(context.Background(), "web-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"info --all --format json", "stop web-1", "info --all --format json"}
+	if got := readCalls(t, calls); strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected calls %v, got %v", expected, got)
+	}
+}
+
+// TestGetInstanceCacheSnapshotInvalidation tests that taking and deleting
+// snapshots drop the cache, since they change the snapshot count
+func TestGetInstanceCacheSnapshotInvalidation(t *testing.T) {
+	binary, calls := writeInfoBinary(t)
+	client := NewMultipassClient(binary)
+
+	if _, err := client.GetInstance(context.Background(), "web-2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.CreateSnapshot(context.Background(), "web-2", "before"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.GetInstance(context.Background(), "web-2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.DeleteSnapshot(context.Background(), "web-2", "before"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.GetInstance(context.Background(), "web-2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"info --all --format json",
+		"snapshot web-2 --name before",
+		"info --all --format json",
+		"delete --purge web-2.before",
+		"info --all --format json",
+	}
+	if got := readCalls(t, calls); strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected calls %v, got %v", expected, got)
+	}
+}
+
+// TestGetInstanceCacheDisabled tests that a zero TTL reads each instance directly
+func TestGetInstanceCacheDisabled(t *testing.T) {
+	binary, calls := writeInfoBinary(t)
+	client := NewMultipassClient(binary)
+	client.SetReadCacheTTL(0)
+
+	if _, err := client.GetInstance(context.Background(), "web-1"); err == nil {
+		t.Error("Expected error from single instance info, got nil")
+	}
+
+	if got := readCalls(t, calls); len(got) != 1 || got[0] != "info web-1 --format json" {
+		t.Errorf("Expected a single instance info call, got %v", got)
+	}
+}
+
+// TestInstanceCacheExpiry tests that expired snapshots are refetched
+func TestInstanceCacheExpiry(t *testing.T) {
+	cache := newInstanceCache(time.Millisecond)
+	fetches := 0
+	fetch := func(ctx context.Context) (map[string]common.MultipassInstance, error) {
+		fetches++
+		return map[string]common.MultipassInstance{"a": {Name: "a"}}, nil
+	}
+
+	if _, exists, _ := cache.lookup(context.Background(), "a", fetch); !exists {
+		t.Error("Expected instance to exist")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, _, err := cache.lookup(context.Background(), "a", fetch); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fetches != 2 {
+		t.Errorf("Expected 2 fetches, got %d", fetches)
+	}
+}
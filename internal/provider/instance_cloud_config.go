@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// filePermissionsPattern matches octal file modes such as "0644"
+var filePermissionsPattern = regexp.MustCompile(`^0?[0-7]{3,4}$`)
+
+// CloudConfigModel describes the cloud_config block.
+type CloudConfigModel struct {
+	Hostname          types.String           `tfsdk:"hostname"`
+	Timezone          types.String           `tfsdk:"timezone"`
+	PackageUpdate     types.Bool             `tfsdk:"package_update"`
+	Packages          []types.String         `tfsdk:"packages"`
+	SSHAuthorizedKeys []types.String         `tfsdk:"ssh_authorized_keys"`
+	RunCmd            []types.String         `tfsdk:"runcmd"`
+	BootCmd           []types.String         `tfsdk:"bootcmd"`
+	Users             []CloudConfigUserModel `tfsdk:"users"`
+	WriteFiles        []CloudConfigFileModel `tfsdk:"write_files"`
+}
+
+// CloudConfigUserModel describes a users block of cloud_config.
+type CloudConfigUserModel struct {
+	Name              types.String   `tfsdk:"name"`
+	Groups            []types.String `tfsdk:"groups"`
+	Sudo              types.String   `tfsdk:"sudo"`
+	Shell             types.String   `tfsdk:"shell"`
+	LockPasswd        types.Bool     `tfsdk:"lock_passwd"`
+	SSHAuthorizedKeys []types.String `tfsdk:"ssh_authorized_keys"`
+}
+
+// CloudConfigFileModel describes a write_files block of cloud_config.
+type CloudConfigFileModel struct {
+	Path        types.String `tfsdk:"path"`
+	Content     types.String `tfsdk:"content"`
+	Permissions types.String `tfsdk:"permissions"`
+	Owner       types.String `tfsdk:"owner"`
+	Append      types.Bool   `tfsdk:"append"`
+}
+
+// validateFilePermissions checks an octal file mode such as "0644"
+func validateFilePermissions(permissions string) error {
+	if !filePermissionsPattern.MatchString(permissions) {
+		return fmt.Errorf("permissions must be an octal mode such as 0644")
+	}
+	return nil
+}
+
+// cloudConfigBlock returns the schema of the cloud_config block
+func cloudConfigBlock() schema.SingleNestedBlock {
+	stringList := func(description string) schema.ListAttribute {
+		return schema.ListAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			ElementType:         types.StringType,
+		}
+	}
+
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Cloud-init settings rendered to a cloud-config document for the launch. " +
+			"When `cloud_init` is also set, they are merged on top of that file: mappings are merged, lists appended and other values replaced. " +
+			"Changing the block replaces the instance.",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: "Hostname of the instance",
+				Optional:            true,
+			},
+			"timezone": schema.StringAttribute{
+				MarkdownDescription: "Timezone of the instance (e.g. 'Asia/Tokyo')",
+				Optional:            true,
+			},
+			"package_update": schema.BoolAttribute{
+				MarkdownDescription: "Whether to update the package index on first boot",
+				Optional:            true,
+			},
+			"packages":            stringList("Packages to install on first boot"),
+			"ssh_authorized_keys": stringList("SSH public keys authorized for the default user"),
+			"runcmd":              stringList("Commands run once on first boot"),
+			"bootcmd":             stringList("Commands run early on every boot"),
+		},
+		Blocks: map[string]schema.Block{
+			"users": schema.ListNestedBlock{
+				MarkdownDescription: "Users to create in addition to the default user, which multipass needs for `multipass shell` and `multipass exec`",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "User name",
+							Required:            true,
+						},
+						"groups": stringList("Supplementary groups of the user"),
+						"sudo": schema.StringAttribute{
+							MarkdownDescription: "Sudo rule of the user (e.g. 'ALL=(ALL) NOPASSWD:ALL')",
+							Optional:            true,
+						},
+						"shell": schema.StringAttribute{
+							MarkdownDescription: "Login shell of the user (e.g. '/bin/bash')",
+							Optional:            true,
+						},
+						"lock_passwd": schema.BoolAttribute{
+							MarkdownDescription: "Whether password login is disabled for the user",
+							Optional:            true,
+						},
+						"ssh_authorized_keys": stringList("SSH public keys authorized for the user"),
+					},
+				},
+			},
+			"write_files": schema.ListNestedBlock{
+				MarkdownDescription: "Files written on first boot",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							MarkdownDescription: "Absolute path of the file in the instance",
+							Required:            true,
+						},
+						"content": schema.StringAttribute{
+							MarkdownDescription: "Content of the file",
+							Required:            true,
+						},
+						"permissions": schema.StringAttribute{
+							MarkdownDescription: "Octal mode of the file (e.g. '0644')",
+							Optional:            true,
+							Validators: []validator.String{
+								stringValueValidator{label: "file permissions", validate: validateFilePermissions},
+							},
+						},
+						"owner": schema.StringAttribute{
+							MarkdownDescription: "Owner of the file as 'user:group'",
+							Optional:            true,
+						},
+						"append": schema.BoolAttribute{
+							MarkdownDescription: "Whether to append to an existing file instead of replacing it",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// render converts the block to a cloud-config mapping, leaving out unset settings
+func (m *CloudConfigModel) render() map[string]interface{} {
+	config := map[string]interface{}{}
+
+	setString(config, "hostname", m.Hostname)
+	setString(config, "timezone", m.Timezone)
+	setBool(config, "package_update", m.PackageUpdate)
+	setStrings(config, "packages", m.Packages)
+	setStrings(config, "ssh_authorized_keys", m.SSHAuthorizedKeys)
+	setStrings(config, "runcmd", m.RunCmd)
+	setStrings(config, "bootcmd", m.BootCmd)
+
+	if len(m.Users) > 0 {
+		// Without "default" cloud-init would skip the user multipass logs in as
+		users := []interface{}{"default"}
+		for _, user := range m.Users {
+			entry := map[string]interface{}{}
+			setString(entry, "name", user.Name)
+			setStrings(entry, "groups", user.Groups)
+			setString(entry, "sudo", user.Sudo)
+			setString(entry, "shell", user.Shell)
+			setBool(entry, "lock_passwd", user.LockPasswd)
+			setStrings(entry, "ssh_authorized_keys", user.SSHAuthorizedKeys)
+			users = append(users, entry)
+		}
+		config["users"] = users
+	}
+
+	if len(m.WriteFiles) > 0 {
+		files := make([]interface{}, 0, len(m.WriteFiles))
+		for _, file := range m.WriteFiles {
+			entry := map[string]interface{}{}
+			setString(entry, "path", file.Path)
+			setString(entry, "content", file.Content)
+			setString(entry, "permissions", file.Permissions)
+			setString(entry, "owner", file.Owner)
+			setBool(entry, "append", file.Append)
+			files = append(files, entry)
+		}
+		config["write_files"] = files
+	}
+
+	return config
+}
+
+// setString adds a string setting unless it is null or unknown
+func setString(config map[string]interface{}, key string, value types.String) {
+	if !value.IsNull() && !value.IsUnknown() {
+		config[key] = value.ValueString()
+	}
+}
+
+// setBool adds a bool setting unless it is null or unknown
+func setBool(config map[string]interface{}, key string, value types.Bool) {
+	if !value.IsNull() && !value.IsUnknown() {
+		config[key] = value.ValueBool()
+	}
+}
+
+// setStrings adds a list setting unless it is empty
+func setStrings(config map[string]interface{}, key string, values []types.String) {
+	if len(values) == 0 {
+		return
+	}
+
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		list = append(list, value.ValueString())
+	}
+	config[key] = list
+}
+
+// renderCloudConfig renders the cloud_config block merged on top of the
+// cloud-init file at base, if any, as a cloud-config document
+func renderCloudConfig(config *CloudConfigModel, base string) (string, error) {
+	merged := config.render()
+
+	if base != "" {
+		content, err := os.ReadFile(base)
+		if err != nil {
+			return "", fmt.Errorf("failed to read cloud-init file: %w", err)
+		}
+
+		baseConfig, err := parseCloudConfig(string(content))
+		if err != nil {
+			return "", err
+		}
+
+		merged = mergeCloudConfig(baseConfig, merged)
+		dedupeDefaultUser(merged)
+	}
+
+	document, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("failed to render cloud-config: %w", err)
+	}
+
+	return cloudConfigHeader + string(document), nil
+}
+
+// dedupeDefaultUser keeps only the first "default" entry of users, which
+// appears twice when the cloud-init file declares it as well
+func dedupeDefaultUser(config map[string]interface{}) {
+	users, ok := config["users"].([]interface{})
+	if !ok {
+		return
+	}
+
+	deduped := make([]interface{}, 0, len(users))
+	seen := false
+	for _, user := range users {
+		if user == "default" {
+			if seen {
+				continue
+			}
+			seen = true
+		}
+		deduped = append(deduped, user)
+	}
+	config["users"] = deduped
+}
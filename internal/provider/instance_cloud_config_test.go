@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+	"gopkg.in/yaml.v3"
+)
+
+// testCloudConfig returns a cloud_config block using most settings
+func testCloudConfig() *CloudConfigModel {
+	return &CloudConfigModel{
+		Hostname:      types.StringValue("web"),
+		Timezone:      types.StringNull(),
+		PackageUpdate: types.BoolValue(true),
+		Packages:      []types.String{types.StringValue("nginx")},
+		RunCmd:        []types.String{types.StringValue("systemctl enable --now nginx")},
+		Users: []CloudConfigUserModel{{
+			Name:       types.StringValue("deploy"),
+			Groups:     []types.String{types.StringValue("sudo")},
+			Sudo:       types.StringValue("ALL=(ALL) NOPASSWD:ALL"),
+			Shell:      types.StringNull(),
+			LockPasswd: types.BoolNull(),
+		}},
+		WriteFiles: []CloudConfigFileModel{{
+			Path:        types.StringValue("/etc/motd"),
+			Content:     types.StringValue("managed by terraform\n"),
+			Permissions: types.StringValue("0644"),
+			Owner:       types.StringNull(),
+			Append:      types.BoolNull(),
+		}},
+	}
+}
+
+func TestRenderCloudConfig(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "cloud-init.yaml")
+	if err := os.WriteFile(base, []byte("#cloud-config\npackages:\n  - git\ntimezone: UTC\n"), 0644); err != nil {
+		t.Fatalf("Failed to create cloud-init file: %v", err)
+	}
+
+	document, err := renderCloudConfig(testCloudConfig(), base)
+	if err != nil {
+		t.Fatalf("renderCloudConfig() error = %v", err)
+	}
+	if !strings.HasPrefix(document, "#cloud-config\n") {
+		t.Errorf("Expected a cloud-config header, got %q", document)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(document), &config); err != nil {
+		t.Fatalf("Rendered invalid YAML: %v", err)
+	}
+
+	// Lists from the file come first, settings absent from the block are kept
+	if packages := config["packages"].([]interface{}); len(packages) != 2 || packages[0] != "git" || packages[1] != "nginx" {
+		t.Errorf("Unexpected packages %v", packages)
+	}
+	if config["timezone"] != "UTC" || config["hostname"] != "web" || config["package_update"] != true {
+		t.Errorf("Unexpected settings %v", config)
+	}
+
+	users := config["users"].([]interface{})
+	if len(users) != 2 || users[0] != "default" {
+		t.Fatalf("Expected the default user to be kept, got %v", users)
+	}
+	user := users[1].(map[string]interface{})
+	if user["name"] != "deploy" || user["sudo"] != "ALL=(ALL) NOPASSWD:ALL" {
+		t.Errorf("Unexpected user %v", user)
+	}
+	if _, ok := user["shell"]; ok {
+		t.Errorf("Expected unset user settings to be left out, got %v", user)
+	}
+
+	file := config["write_files"].([]interface{})[0].(map[string]interface{})
+	if file["path"] != "/etc/motd" || file["permissions"] != "0644" {
+		t.Errorf("Unexpected file %v", file)
+	}
+}
+
+func TestRenderCloudConfigFileUsers(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "cloud-init.yaml")
+	content := "#cloud-config\nusers:\n  - default\n  - name: admin\n"
+	if err := os.WriteFile(base, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create cloud-init file: %v", err)
+	}
+
+	document, err := renderCloudConfig(testCloudConfig(), base)
+	if err != nil {
+		t.Fatalf("renderCloudConfig() error = %v", err)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(document), &config); err != nil {
+		t.Fatalf("Rendered invalid YAML: %v", err)
+	}
+
+	// The default user is declared by both, but listed once
+	var names []interface{}
+	for _, user := range config["users"].([]interface{}) {
+		if entry, ok := user.(map[string]interface{}); ok {
+			names = append(names, entry["name"])
+		} else {
+			names = append(names, user)
+		}
+	}
+	if want := []interface{}{"default", "admin", "deploy"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Unexpected users %v, want %v", names, want)
+	}
+}
+
+func TestRenderCloudConfigMissingFile(t *testing.T) {
+	if _, err := renderCloudConfig(testCloudConfig(), filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing cloud-init file")
+	}
+}
+
+// TestLaunchCloudInitData tests that a rendered cloud-config reaches
+// multipass on stdin rather than through a file
+func TestLaunchCloudInitData(t *testing.T) {
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	binary, calls := writeFakeMultipass(t, fmt.Sprintf(`cat > %s
+exit 0
+`, stdin))
+
+	document, err := renderCloudConfig(testCloudConfig(), "")
+	if err != nil {
+		t.Fatalf("renderCloudConfig() error = %v", err)
+	}
+
+	client := NewMultipassClient(binary)
+	if err := client.Launch(context.Background(), &common.LaunchOptions{Name: "web", CloudInitData: document}); err != nil {
+		t.Fatalf("Launch() error = %v", err)
+	}
+
+	if got := readCalls(t, calls); len(got) != 1 || got[0] != "launch --name web --cloud-init -" {
+		t.Errorf("Unexpected calls %v", got)
+	}
+	if content, err := os.ReadFile(stdin); err != nil || string(content) != document {
+		t.Errorf("Unexpected stdin %q, %v", content, err)
+	}
+
+	// A cloud-init file cannot be passed as well
+	err = client.Launch(context.Background(), &common.LaunchOptions{Name: "web", CloudInit: "cloud-init.yaml", CloudInitData: document})
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("Expected a combined cloud-init error, got %v", err)
+	}
+}
+
+func TestModifyPlanCloudConfigReplacement(t *testing.T) {
+	prior := testInstanceModel("web")
+	prior.CloudConfig = testCloudConfig()
+	planned := testInstanceModel("web")
+	planned.CloudConfig = testCloudConfig()
+	planned.CloudConfig.Packages = append(planned.CloudConfig.Packages, types.StringValue("git"))
+
+	diags := runInstanceModifyPlan(t, &prior, &planned)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags.Warnings()[0].Detail(), "cloud_config: block changed") {
+		t.Errorf("Expected a replacement warning naming cloud_config, got %v", diags)
+	}
+}
+
+func TestValidateFilePermissions(t *testing.T) {
+	for _, valid := range []string{"0644", "644", "0755", "1777"} {
+		if err := validateFilePermissions(valid); err != nil {
+			t.Errorf("validateFilePermissions(%q) unexpected error: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"rw-r--r--", "0844", "64", "006440"} {
+		if err := validateFilePermissions(invalid); err == nil {
+			t.Errorf("validateFilePermissions(%q) expected an error", invalid)
+		}
+	}
+}
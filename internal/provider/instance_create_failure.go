@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Ways of handling an instance that was launched but not fully created
+const (
+	createFailureTaint  = "taint"
+	createFailureDelete = "delete"
+	createFailureKeep   = "keep"
+)
+
+// createFailureModes lists the accepted on_create_failure values
+var createFailureModes = []string{createFailureTaint, createFailureDelete, createFailureKeep}
+
+// createCleanupTimeout bounds the commands run after a failed create. They
+// use a fresh deadline since the create timeout may be what expired.
+const createCleanupTimeout = 5 * time.Minute
+
+// recoverFailedCreate reports a failed create and deals with an instance it
+// may have left behind according to on_create_failure, so that the instance
+// is not orphaned and the next apply does not fail with a name collision
+func (r *InstanceResource) recoverFailedCreate(ctx context.Context, data *InstanceResourceModel, resp *resource.CreateResponse, cause error) {
+	name := data.Name.ValueString()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), createCleanupTimeout)
+	defer cancel()
+
+	instance, err := r.client.GetInstance(ctx, name)
+	if errors.Is(err, ErrInstanceNotFound) {
+		// Nothing was created, so there is nothing to clean up
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create instance, got error: %s", cause))
+		return
+	}
+
+	mode := data.OnCreateFailure.ValueString()
+	if mode == "" {
+		mode = createFailureTaint
+	}
+
+	tflog.Warn(ctx, "multipass instance partially created", map[string]interface{}{
+		"name":              name,
+		"on_create_failure": mode,
+		"error":             cause.Error(),
+	})
+
+	detail := fmt.Sprintf("Creating instance %q failed after it was launched: %s", name, cause)
+
+	switch mode {
+	case createFailureKeep:
+		resp.Diagnostics.AddError("Instance Partially Created", detail+"\n\n"+
+			fmt.Sprintf("The instance was left in place and is not managed by Terraform (on_create_failure = %q). "+
+				"Import it, or delete it with `multipass delete --purge %s`, before applying again.", mode, name))
+		return
+
+	case createFailureDelete:
+		deleteErr := r.client.DeleteInstance(ctx, name, true)
+		if deleteErr == nil {
+			resp.Diagnostics.AddError("Instance Partially Created", detail+"\n\n"+
+				fmt.Sprintf("The instance was deleted (on_create_failure = %q).", mode))
+			return
+		}
+		detail += fmt.Sprintf("\n\nDeleting the instance also failed: %s", deleteErr)
+	}
+
+	// Returning state together with an error makes Terraform mark the
+	// resource as tainted, so it is replaced on the next apply
+	data.Id = data.Name
+	data.State = types.StringValue(common.StateUnknown.String())
+	data.IPv4 = types.ListValueMust(types.StringType, []attr.Value{})
+	if instance != nil {
+		r.updateModelFromInstance(data, instance)
+	}
+	if data.Release.IsUnknown() {
+		data.Release = types.StringNull()
+	}
+	if data.ImageHash.IsUnknown() {
+		data.ImageHash = types.StringNull()
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(setInstanceIdentity(ctx, resp.Identity, name)...)
+	resp.Diagnostics.AddError("Instance Partially Created", detail+"\n\n"+
+		"The instance was saved to state as tainted and will be replaced on the next apply.")
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// writeCreateFailureBinary creates a fake multipass binary whose launch
+// fails with launchError after (if created) leaving the instance behind
+func writeCreateFailureBinary(t *testing.T, launchError string, created bool) (string, string) {
+	t.Helper()
+
+	info := `{"errors": [], "info": {}}`
+	if created {
+		info = `{"errors": [], "info": {"web": {"state": "Starting"}}}`
+	}
+	return writeFakeMultipass(t, fmt.Sprintf(`case "$1" in
+  launch)
+    echo '%s' >&2
+    exit 2
+    ;;
+  info)
+    echo '%s'
+    ;;
+esac
+exit 0
+`, launchError, info))
+}
+
+// runInstanceCreate runs Create on a plan for instance "web" and returns the response
+func runInstanceCreate(t *testing.T, binary string, onCreateFailure string) *resource.CreateResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	client := NewMultipassClient(binary)
+	client.SetReadCacheTTL(0)
+	r := &InstanceResource{client: client}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	data := testInstanceModel("web")
+	data.Id = types.StringUnknown()
+	data.Image = types.StringNull()
+	data.Memory = types.StringNull()
+	data.MemoryBytes = types.Int64Null()
+	data.State = types.StringUnknown()
+	data.IPv4 = types.ListUnknown(types.StringType)
+	if onCreateFailure != "" {
+		data.OnCreateFailure = types.StringValue(onCreateFailure)
+	}
+
+	req := resource.CreateRequest{
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema},
+		Config: tfsdk.Config{Schema: schemaResp.Schema},
+	}
+	if diags := req.Plan.Set(ctx, &data); diags.HasError() {
+		t.Fatalf("Unable to build plan: %v", diags)
+	}
+	req.Config.Raw = req.Plan.Raw
+
+	resp := &resource.CreateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+	r.Create(ctx, req, resp)
+
+	return resp
+}
+
+func TestCreateFailureModes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		created     bool
+		wantState   bool
+		wantDelete  bool
+		wantSummary string
+	}{
+		{"Taint by default", "", true, true, false, "Instance Partially Created"},
+		{"Taint", "taint", true, true, false, "Instance Partially Created"},
+		{"Delete", "delete", true, false, true, "Instance Partially Created"},
+		{"Keep", "keep", true, false, false, "Instance Partially Created"},
+		{"Nothing created", "taint", false, false, false, "Client Error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binary, calls := writeCreateFailureBinary(t, "launch failed: timed out waiting for response", tc.created)
+			resp := runInstanceCreate(t, binary, tc.mode)
+
+			errs := resp.Diagnostics.Errors()
+			if len(errs) != 1 || errs[0].Summary() != tc.wantSummary {
+				t.Fatalf("Expected one %q error, got %v", tc.wantSummary, resp.Diagnostics)
+			}
+
+			if got := !resp.State.Raw.IsNull(); got != tc.wantState {
+				t.Errorf("Expected state saved = %t, got %t", tc.wantState, got)
+			}
+
+			deleted := false
+			for _, call := range readCalls(t, calls) {
+				if strings.HasPrefix(call, "delete") {
+					deleted = true
+				}
+			}
+			if deleted != tc.wantDelete {
+				t.Errorf("Expected delete called = %t, got %t", tc.wantDelete, deleted)
+			}
+		})
+	}
+}
+
+func TestCreateFailureTaintedState(t *testing.T) {
+	binary, _ := writeCreateFailureBinary(t, "launch failed: timed out waiting for response", true)
+	resp := runInstanceCreate(t, binary, "taint")
+
+	var state InstanceResourceModel
+	if diags := resp.State.Get(context.Background(), &state); diags.HasError() {
+		t.Fatalf("Unable to read state: %v", diags)
+	}
+	if state.Id.ValueString() != "web" || state.State.ValueString() != "Starting" {
+		t.Errorf("Expected tainted state for web in state Starting, got %+v", state)
+	}
+}
+
+func TestCreateFailureExistingInstance(t *testing.T) {
+	binary, calls := writeCreateFailureBinary(t, `launch failed: instance "web" already exists`, true)
+	resp := runInstanceCreate(t, binary, "delete")
+
+	if !resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Fatalf("Expected an error without state, got %v", resp.Diagnostics)
+	}
+	for _, call := range readCalls(t, calls) {
+		if !strings.HasPrefix(call, "launch") {
+			t.Errorf("Expected an existing instance to be left alone, got call %q", call)
+		}
+	}
+}
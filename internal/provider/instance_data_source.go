@@ -2,11 +2,16 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sh05/terraform-provider-multipass/internal/common"
@@ -14,6 +19,7 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ datasource.DataSource = &InstanceDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &InstanceDataSource{}
 
 func NewInstanceDataSource() datasource.DataSource {
 	return &InstanceDataSource{}
@@ -26,19 +32,46 @@ type InstanceDataSource struct {
 
 // InstanceDataSourceModel describes the data source data model.
 type InstanceDataSourceModel struct {
-	Id        types.String        `tfsdk:"id"`
-	Name      types.String        `tfsdk:"name"`
-	Instance  *InstanceDataModel  `tfsdk:"instance"`
-	Instances []InstanceDataModel `tfsdk:"instances"`
+	Id            types.String          `tfsdk:"id"`
+	Name          types.String          `tfsdk:"name"`
+	Filters       []InstanceFilterModel `tfsdk:"filter"`
+	SortBy        types.String          `tfsdk:"sort_by"`
+	SortOrder     types.String          `tfsdk:"sort_order"`
+	Instance      *InstanceDataModel    `tfsdk:"instance"`
+	Instances     []InstanceDataModel   `tfsdk:"instances"`
+	Names         types.List            `tfsdk:"names"`
+	FailIfMissing types.Bool            `tfsdk:"fail_if_missing"`
+	Exists        types.Bool            `tfsdk:"exists"`
 }
 
 // InstanceDataModel represents an instance in the data source
 type InstanceDataModel struct {
-	Name      types.String `tfsdk:"name"`
-	State     types.String `tfsdk:"state"`
-	IPv4      types.List   `tfsdk:"ipv4"`
-	Release   types.String `tfsdk:"release"`
-	ImageHash types.String `tfsdk:"image_hash"`
+	Name          types.String     `tfsdk:"name"`
+	State         types.String     `tfsdk:"state"`
+	IPv4          types.List       `tfsdk:"ipv4"`
+	Release       types.String     `tfsdk:"release"`
+	ImageHash     types.String     `tfsdk:"image_hash"`
+	ImageRelease  types.String     `tfsdk:"image_release"`
+	CPUCount      types.Int64      `tfsdk:"cpu_count"`
+	Load          types.List       `tfsdk:"load"`
+	Memory        *UsageDataModel  `tfsdk:"memory"`
+	Disk          *UsageDataModel  `tfsdk:"disk"`
+	Mounts        []MountDataModel `tfsdk:"mounts"`
+	SnapshotCount types.Int64      `tfsdk:"snapshot_count"`
+}
+
+// UsageDataModel represents used and total bytes of memory or disk
+type UsageDataModel struct {
+	Used  types.Int64 `tfsdk:"used"`
+	Total types.Int64 `tfsdk:"total"`
+}
+
+// MountDataModel represents a host directory mounted into an instance
+type MountDataModel struct {
+	Source types.String `tfsdk:"source"`
+	Target types.String `tfsdk:"target"`
+	UIDMap types.Map    `tfsdk:"uid_map"`
+	GIDMap types.Map    `tfsdk:"gid_map"`
 }
 
 func (d *InstanceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -55,66 +88,181 @@ func (d *InstanceDataSource) Schema(ctx context.Context, req datasource.SchemaRe
 				Computed:            true,
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Name of the instance to retrieve. If not specified, all instances will be returned.",
+				MarkdownDescription: "Name of the instance to retrieve. If not specified, all instances matching the `filter` blocks will be returned.",
+				Optional:            true,
+			},
+			"fail_if_missing": schema.BoolAttribute{
+				MarkdownDescription: "Whether a missing `name` instance is an error. When false, `exists` is false and `instance` is null instead. Defaults to true.",
+				Optional:            true,
+			},
+			"exists": schema.BoolAttribute{
+				MarkdownDescription: "Whether the named instance exists, or whether any instance matched when listing",
+				Computed:            true,
+			},
+			"sort_by": schema.StringAttribute{
+				MarkdownDescription: "Order of `instances` and `names`: 'name', 'state' or 'release'. Ties are ordered by name. Defaults to 'name'.",
+				Optional:            true,
+				Validators: []validator.String{
+					oneOfValidator("sort key", instanceSortKeys...),
+				},
+			},
+			"sort_order": schema.StringAttribute{
+				MarkdownDescription: "Sort direction: 'asc' or 'desc'. Defaults to 'asc'.",
 				Optional:            true,
+				Validators: []validator.String{
+					oneOfValidator("sort order", instanceSortOrders...),
+				},
+			},
+			"names": schema.ListAttribute{
+				MarkdownDescription: "Names of the returned instances, in the same order as `instances`",
+				Computed:            true,
+				ElementType:         types.StringType,
 			},
 			"instance": schema.SingleNestedAttribute{
 				MarkdownDescription: "Instance details (when querying by name)",
 				Computed:            true,
+				Attributes:          instanceDataAttributes(),
+			},
+			"instances": schema.ListNestedAttribute{
+				MarkdownDescription: "List of all instances sorted by name (when not querying by name)",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: instanceDataAttributes(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"filter": dataSourceFilterBlock(instanceFilterDescription + " Cannot be combined with `name`."),
+		},
+	}
+}
+
+func (d *InstanceDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data InstanceDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Name.IsNull() && len(data.Filters) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("filter"), "Invalid Attribute Combination",
+			"filter blocks select from all instances and cannot be combined with name.")
+	}
+}
+
+// dataSourceFilterBlock builds the filter block from instanceFilterAttributes
+func dataSourceFilterBlock(description string) schema.ListNestedBlock {
+	attributes := make(map[string]schema.Attribute)
+	for name, filter := range instanceFilterAttributes() {
+		switch {
+		case filter.valueType.Equal(types.StringType):
+			attributes[name] = schema.StringAttribute{MarkdownDescription: filter.description, Optional: true, Validators: filter.validators}
+		case filter.valueType.Equal(types.BoolType):
+			attributes[name] = schema.BoolAttribute{MarkdownDescription: filter.description, Optional: true}
+		default:
+			attributes[name] = schema.ListAttribute{MarkdownDescription: filter.description, Optional: true, ElementType: filter.valueType.(types.ListType).ElemType}
+		}
+	}
+
+	return schema.ListNestedBlock{
+		MarkdownDescription: description,
+		NestedObject:        schema.NestedBlockObject{Attributes: attributes},
+	}
+}
+
+// instanceDataAttributes returns the attributes describing one instance,
+// shared by the single instance and list modes
+func instanceDataAttributes() map[string]schema.Attribute {
+	usageAttributes := func(kind string) map[string]schema.Attribute {
+		return map[string]schema.Attribute{
+			"used": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Used %s in bytes", kind),
+				Computed:            true,
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Total %s in bytes", kind),
+				Computed:            true,
+			},
+		}
+	}
+
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Instance name",
+			Computed:            true,
+		},
+		"state": schema.StringAttribute{
+			MarkdownDescription: "Instance state",
+			Computed:            true,
+		},
+		"ipv4": schema.ListAttribute{
+			MarkdownDescription: "IPv4 addresses",
+			Computed:            true,
+			ElementType:         types.StringType,
+		},
+		"release": schema.StringAttribute{
+			MarkdownDescription: "Ubuntu release",
+			Computed:            true,
+		},
+		"image_hash": schema.StringAttribute{
+			MarkdownDescription: "Image hash",
+			Computed:            true,
+		},
+		"image_release": schema.StringAttribute{
+			MarkdownDescription: "Release of the image the instance was launched from (e.g. '22.04 LTS')",
+			Computed:            true,
+		},
+		"cpu_count": schema.Int64Attribute{
+			MarkdownDescription: "Number of CPUs. Null when the instance is not running.",
+			Computed:            true,
+		},
+		"load": schema.ListAttribute{
+			MarkdownDescription: "1, 5 and 15 minute load averages. Null when the instance is not running.",
+			Computed:            true,
+			ElementType:         types.Float64Type,
+		},
+		"memory": schema.SingleNestedAttribute{
+			MarkdownDescription: "Memory usage. Null when the instance is not running.",
+			Computed:            true,
+			Attributes:          usageAttributes("memory"),
+		},
+		"disk": schema.SingleNestedAttribute{
+			MarkdownDescription: "Disk usage summed over all instance disks. Null when the instance is not running.",
+			Computed:            true,
+			Attributes:          usageAttributes("disk space"),
+		},
+		"mounts": schema.ListNestedAttribute{
+			MarkdownDescription: "Host directories mounted into the instance, sorted by target path",
+			Computed:            true,
+			NestedObject: schema.NestedAttributeObject{
 				Attributes: map[string]schema.Attribute{
-					"name": schema.StringAttribute{
-						MarkdownDescription: "Instance name",
+					"source": schema.StringAttribute{
+						MarkdownDescription: "Directory on the host",
 						Computed:            true,
 					},
-					"state": schema.StringAttribute{
-						MarkdownDescription: "Instance state",
+					"target": schema.StringAttribute{
+						MarkdownDescription: "Mount point inside the instance",
 						Computed:            true,
 					},
-					"ipv4": schema.ListAttribute{
-						MarkdownDescription: "IPv4 addresses",
+					"uid_map": schema.MapAttribute{
+						MarkdownDescription: "Host user IDs mapped to instance user IDs",
 						Computed:            true,
 						ElementType:         types.StringType,
 					},
-					"release": schema.StringAttribute{
-						MarkdownDescription: "Ubuntu release",
+					"gid_map": schema.MapAttribute{
+						MarkdownDescription: "Host group IDs mapped to instance group IDs",
 						Computed:            true,
-					},
-					"image_hash": schema.StringAttribute{
-						MarkdownDescription: "Image hash",
-						Computed:            true,
-					},
-				},
-			},
-			"instances": schema.ListNestedAttribute{
-				MarkdownDescription: "List of all instances (when not querying by name)",
-				Computed:            true,
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							MarkdownDescription: "Instance name",
-							Computed:            true,
-						},
-						"state": schema.StringAttribute{
-							MarkdownDescription: "Instance state",
-							Computed:            true,
-						},
-						"ipv4": schema.ListAttribute{
-							MarkdownDescription: "IPv4 addresses",
-							Computed:            true,
-							ElementType:         types.StringType,
-						},
-						"release": schema.StringAttribute{
-							MarkdownDescription: "Ubuntu release",
-							Computed:            true,
-						},
-						"image_hash": schema.StringAttribute{
-							MarkdownDescription: "Image hash",
-							Computed:            true,
-						},
+						ElementType:         types.StringType,
 					},
 				},
 			},
 		},
+		"snapshot_count": schema.Int64Attribute{
+			MarkdownDescription: "Number of snapshots. Null when multipass does not report snapshots.",
+			Computed:            true,
+		},
 	}
 }
 
@@ -153,7 +301,18 @@ func (d *InstanceDataSource) Read(ctx context.Context, req datasource.ReadReques
 		instanceName := data.Name.ValueString()
 		tflog.Trace(ctx, "reading multipass instance", map[string]interface{}{"name": instanceName})
 
-		instance, err := d.client.GetInstance(instanceName)
+		instance, err := d.client.GetInstance(ctx, instanceName)
+		if errors.Is(err, ErrInstanceNotFound) && !data.FailIfMissing.IsNull() && !data.FailIfMissing.ValueBool() {
+			tflog.Debug(ctx, "multipass instance not found", map[string]interface{}{"name": instanceName})
+
+			data.Instance = nil
+			data.Names = types.ListValueMust(types.StringType, []attr.Value{})
+			data.Exists = types.BoolValue(false)
+			data.Id = types.StringValue(instanceName)
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read instance, got error: %s", err))
 			return
@@ -162,24 +321,39 @@ func (d *InstanceDataSource) Read(ctx context.Context, req datasource.ReadReques
 		// Convert to data model
 		instanceData := d.convertToDataModel(instance)
 		data.Instance = &instanceData
+		data.Names = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(instance.Name)})
+		data.Exists = types.BoolValue(true)
 		data.Id = types.StringValue(instanceName)
 	} else {
 		// List all instances
 		tflog.Trace(ctx, "listing all multipass instances")
 
-		instances, err := d.client.ListInstances()
+		filters, err := compileInstanceFilters(data.Filters)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("filter"), "Invalid Filter", err.Error())
+			return
+		}
+
+		instances, err := d.client.ListInstanceDetails(ctx)
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list instances, got error: %s", err))
 			return
 		}
 
+		instances = filterInstances(instances, filters)
+		sortInstances(instances, data.SortBy.ValueString(), data.SortOrder.ValueString() == "desc")
+
 		// Convert to data models
 		instanceDataList := make([]InstanceDataModel, len(instances))
+		nameValues := make([]attr.Value, len(instances))
 		for i, instance := range instances {
 			instanceDataList[i] = d.convertToDataModel(&instance)
+			nameValues[i] = types.StringValue(instance.Name)
 		}
 
 		data.Instances = instanceDataList
+		data.Names = types.ListValueMust(types.StringType, nameValues)
+		data.Exists = types.BoolValue(len(instances) > 0)
 		data.Id = types.StringValue("all-instances")
 	}
 
@@ -190,10 +364,15 @@ func (d *InstanceDataSource) Read(ctx context.Context, req datasource.ReadReques
 // convertToDataModel converts a common.MultipassInstance to InstanceDataModel
 func (d *InstanceDataSource) convertToDataModel(instance *common.MultipassInstance) InstanceDataModel {
 	model := InstanceDataModel{
-		Name:      types.StringValue(instance.Name),
-		State:     types.StringValue(instance.State),
-		Release:   types.StringValue(instance.Release),
-		ImageHash: types.StringValue(instance.ImageHash),
+		Name:          types.StringValue(instance.Name),
+		State:         types.StringValue(instance.State.String()),
+		Release:       types.StringValue(instance.Release),
+		ImageHash:     types.StringValue(instance.ImageHash),
+		ImageRelease:  types.StringValue(instance.ImageRelease),
+		CPUCount:      types.Int64Null(),
+		Load:          types.ListNull(types.Float64Type),
+		SnapshotCount: types.Int64Null(),
+		Mounts:        []MountDataModel{},
 	}
 
 	// Convert IPv4 addresses to list
@@ -207,5 +386,72 @@ func (d *InstanceDataSource) convertToDataModel(instance *common.MultipassInstan
 		model.IPv4 = types.ListValueMust(types.StringType, []attr.Value{})
 	}
 
+	// Stopped instances report an empty CPU count
+	if instance.CPUCount != nil && *instance.CPUCount > 0 {
+		model.CPUCount = types.Int64Value(int64(*instance.CPUCount))
+	}
+	if instance.SnapshotCount != nil {
+		model.SnapshotCount = types.Int64Value(int64(*instance.SnapshotCount))
+	}
+
+	if len(instance.Load) > 0 {
+		loadValues := make([]attr.Value, len(instance.Load))
+		for i, load := range instance.Load {
+			loadValues[i] = types.Float64Value(load)
+		}
+		model.Load = types.ListValueMust(types.Float64Type, loadValues)
+	}
+
+	if instance.Memory != nil && instance.Memory.Total > 0 {
+		model.Memory = &UsageDataModel{
+			Used:  types.Int64Value(int64(instance.Memory.Used)),
+			Total: types.Int64Value(int64(instance.Memory.Total)),
+		}
+	}
+
+	// Instances usually have a single disk, but report the sum if there are more
+	if len(instance.Disks) > 0 {
+		var used, total int64
+		for _, disk := range instance.Disks {
+			used += int64(disk.Used)
+			total += int64(disk.Total)
+		}
+		if total > 0 {
+			model.Disk = &UsageDataModel{
+				Used:  types.Int64Value(used),
+				Total: types.Int64Value(total),
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(instance.Mounts))
+	for target := range instance.Mounts {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		mount := instance.Mounts[target]
+		model.Mounts = append(model.Mounts, MountDataModel{
+			Source: types.StringValue(mount.SourcePath),
+			Target: types.StringValue(target),
+			UIDMap: idMappingsToMap(mount.UIDMappings),
+			GIDMap: idMappingsToMap(mount.GIDMappings),
+		})
+	}
+
 	return model
 }
+
+// idMappingsToMap converts multipass "host:instance" ID mappings to a map
+func idMappingsToMap(mappings []string) types.Map {
+	values := make(map[string]attr.Value, len(mappings))
+	for _, mapping := range mappings {
+		host, instance, found := strings.Cut(mapping, ":")
+		if !found {
+			continue
+		}
+		values[host] = types.StringValue(instance)
+	}
+	return types.MapValueMust(types.StringType, values)
+}
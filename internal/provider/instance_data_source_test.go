@@ -1,9 +1,13 @@
 package provider
 
 import (
+	"encoding/json"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -241,3 +245,182 @@ data "multipass_instance" "test1" {}
 data "multipass_instance" "test2" {}
 data "multipass_instance" "test3" {}
 `
+
+// multipassInfoRunning is `multipass info --format json` output for a running instance
+const multipassInfoRunning = `{
+  "errors": [],
+  "info": {
+    "web": {
+      "cpu_count": "2",
+      "disks": {"sda1": {"total": "5116440064", "used": "2034593280"}},
+      "image_hash": "b1fb5b8e",
+      "image_release": "22.04 LTS",
+      "ipv4": ["10.0.0.2"],
+      "load": [0.12, 0.05, 0.01],
+      "memory": {"total": 1013899264, "used": 203100160},
+      "mounts": {
+        "/home/ubuntu/src": {
+          "gid_mappings": ["1000:default"],
+          "source_path": "/home/user/src",
+          "uid_mappings": ["1000:default"]
+        }
+      },
+      "release": "Ubuntu 22.04.3 LTS",
+      "snapshot_count": "1",
+      "state": "Running"
+    },
+    "db": {
+      "cpu_count": "",
+      "disks": {"sda1": {}},
+      "image_hash": "b1fb5b8e",
+      "image_release": "22.04 LTS",
+      "ipv4": [],
+      "load": [],
+      "memory": {},
+      "mounts": {},
+      "release": "",
+      "state": "Stopped"
+    }
+  }
+}`
+
+func TestConvertToDataModel(t *testing.T) {
+	var info common.MultipassInstanceInfo
+	if err := json.Unmarshal([]byte(multipassInfoRunning), &info); err != nil {
+		t.Fatalf("Failed to parse info output: %v", err)
+	}
+
+	d := &InstanceDataSource{}
+
+	web := info.Info["web"]
+	model := d.convertToDataModel(&web)
+
+	if !model.CPUCount.Equal(types.Int64Value(2)) {
+		t.Errorf("cpu_count = %s, want 2", model.CPUCount)
+	}
+	if !model.SnapshotCount.Equal(types.Int64Value(1)) {
+		t.Errorf("snapshot_count = %s, want 1", model.SnapshotCount)
+	}
+	if !model.ImageRelease.Equal(types.StringValue("22.04 LTS")) {
+		t.Errorf("image_release = %s, want 22.04 LTS", model.ImageRelease)
+	}
+	if len(model.Load.Elements()) != 3 {
+		t.Errorf("load = %s, want 3 values", model.Load)
+	}
+	if model.Memory == nil || model.Memory.Used.ValueInt64() != 203100160 || model.Memory.Total.ValueInt64() != 1013899264 {
+		t.Errorf("memory = %+v, want used 203100160 of 1013899264", model.Memory)
+	}
+	if model.Disk == nil || model.Disk.Used.ValueInt64() != 2034593280 || model.Disk.Total.ValueInt64() != 5116440064 {
+		t.Errorf("disk = %+v, want used 2034593280 of 5116440064", model.Disk)
+	}
+	if len(model.Mounts) != 1 {
+		t.Fatalf("mounts = %+v, want one mount", model.Mounts)
+	}
+	mount := model.Mounts[0]
+	if mount.Source.ValueString() != "/home/user/src" || mount.Target.ValueString() != "/home/ubuntu/src" {
+		t.Errorf("mount = %s -> %s, want /home/user/src -> /home/ubuntu/src", mount.Source, mount.Target)
+	}
+	if got := mount.UIDMap.Elements()["1000"]; !got.Equal(types.StringValue("default")) {
+		t.Errorf("uid_map[1000] = %s, want default", got)
+	}
+
+	// Stopped instances report empty details, which become null
+	db := info.Info["db"]
+	model = d.convertToDataModel(&db)
+
+	if !model.CPUCount.IsNull() {
+		t.Errorf("cpu_count = %s, want null", model.CPUCount)
+	}
+	if !model.Load.IsNull() {
+		t.Errorf("load = %s, want null", model.Load)
+	}
+	if model.Memory != nil || model.Disk != nil {
+		t.Errorf("memory = %+v, disk = %+v, want null", model.Memory, model.Disk)
+	}
+	if len(model.Mounts) != 0 {
+		t.Errorf("mounts = %+v, want none", model.Mounts)
+	}
+}
+
+func TestAccInstanceDataSource_Filter(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceDataSourceConfigFilter,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.multipass_instance.test", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.multipass_instance.test", "names.0", "test-filter-web"),
+					resource.TestCheckResourceAttr("data.multipass_instance.test", "instances.0.state", "Running"),
+				),
+			},
+		},
+	})
+}
+
+const testAccInstanceDataSourceConfigFilter = `
+resource "multipass_instance" "test" {
+  name  = "test-filter-web"
+  image = "22.04"
+}
+
+data "multipass_instance" "test" {
+  filter {
+    name     = "test-filter-*"
+    states   = ["Running"]
+    has_ipv4 = true
+  }
+
+  sort_by    = "name"
+  sort_order = "desc"
+
+  depends_on = [multipass_instance.test]
+}
+`
+
+func TestAccInstanceDataSource_NameWithFilter(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccInstanceDataSourceConfigNameWithFilter,
+				ExpectError: regexp.MustCompile(`cannot be combined with name`),
+			},
+		},
+	})
+}
+
+const testAccInstanceDataSourceConfigNameWithFilter = `
+data "multipass_instance" "test" {
+  name = "web-1"
+
+  filter {
+    states = ["Running"]
+  }
+}
+`
+
+func TestAccInstanceDataSource_FailIfMissingFalse(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceDataSourceConfigFailIfMissing,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.multipass_instance.test", "exists", "false"),
+					resource.TestCheckNoResourceAttr("data.multipass_instance.test", "instance.name"),
+				),
+			},
+		},
+	})
+}
+
+const testAccInstanceDataSourceConfigFailIfMissing = `
+data "multipass_instance" "test" {
+  name            = "non-existent-instance-12345"
+  fail_if_missing = false
+}
+`
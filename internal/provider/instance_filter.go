@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Sort keys and orders accepted by the instance data source
+var (
+	instanceSortKeys   = []string{"name", "state", "release"}
+	instanceSortOrders = []string{"asc", "desc"}
+)
+
+// InstanceFilterModel describes a filter block of the instance data source.
+// An instance matches a filter when it satisfies every criterion set in it.
+type InstanceFilterModel struct {
+	Name      types.String   `tfsdk:"name"`
+	NameRegex types.String   `tfsdk:"name_regex"`
+	States    []types.String `tfsdk:"states"`
+	Release   types.String   `tfsdk:"release"`
+	HasIPv4   types.Bool     `tfsdk:"has_ipv4"`
+}
+
+// instanceFilterDescription describes the filter block of the instance data
+// source and list resource
+const instanceFilterDescription = "Restricts the listed instances. An instance is returned when it matches every criterion of at least one filter block."
+
+// instanceFilterAttribute describes an attribute of the filter block
+type instanceFilterAttribute struct {
+	description string
+	valueType   attr.Type
+	validators  []validator.String
+}
+
+// instanceFilterAttributes returns the attributes of the filter block. The
+// instance data source and list resource build their schemas from them, as
+// the framework has a schema package for each.
+func instanceFilterAttributes() map[string]instanceFilterAttribute {
+	return map[string]instanceFilterAttribute{
+		"name": {
+			description: "Glob pattern the instance name must match (e.g. 'web-*')",
+			valueType:   types.StringType,
+		},
+		"name_regex": {
+			description: "Regular expression the instance name must match",
+			valueType:   types.StringType,
+			validators:  []validator.String{regexValidator()},
+		},
+		"states": {
+			description: "Instance states to include (e.g. ['Running', 'Suspended']), compared case-insensitively",
+			valueType:   types.ListType{ElemType: types.StringType},
+		},
+		"release": {
+			description: "Text the Ubuntu release or image release must contain (e.g. '22.04')",
+			valueType:   types.StringType,
+		},
+		"has_ipv4": {
+			description: "Whether the instance must (true) or must not (false) have an IPv4 address",
+			valueType:   types.BoolType,
+		},
+	}
+}
+
+// instanceFilter is a compiled filter block
+type instanceFilter struct {
+	glob    string
+	regex   *regexp.Regexp
+	states  []string
+	release string
+	hasIPv4 *bool
+}
+
+// compileInstanceFilters converts filter blocks into matchers
+func compileInstanceFilters(models []InstanceFilterModel) ([]instanceFilter, error) {
+	filters := make([]instanceFilter, 0, len(models))
+	for _, model := range models {
+		filter := instanceFilter{
+			glob:    model.Name.ValueString(),
+			release: model.Release.ValueString(),
+		}
+
+		if filter.glob != "" {
+			if _, err := path.Match(filter.glob, ""); err != nil {
+				return nil, fmt.Errorf("invalid name pattern %q: %w", filter.glob, err)
+			}
+		}
+
+		if pattern := model.NameRegex.ValueString(); pattern != "" {
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid name_regex %q: %w", pattern, err)
+			}
+			filter.regex = regex
+		}
+
+		for _, state := range model.States {
+			filter.states = append(filter.states, state.ValueString())
+		}
+
+		if !model.HasIPv4.IsNull() && !model.HasIPv4.IsUnknown() {
+			hasIPv4 := model.HasIPv4.ValueBool()
+			filter.hasIPv4 = &hasIPv4
+		}
+
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// matches reports whether the instance satisfies every criterion of the filter
+func (f instanceFilter) matches(instance common.MultipassInstance) bool {
+	if f.glob != "" {
+		if matched, _ := path.Match(f.glob, instance.Name); !matched {
+			return false
+		}
+	}
+
+	if f.regex != nil && !f.regex.MatchString(instance.Name) {
+		return false
+	}
+
+	if len(f.states) > 0 {
+		found := false
+		for _, state := range f.states {
+			if strings.EqualFold(state, instance.State.String()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// multipass reports "Ubuntu 22.04.3 LTS" as release and "22.04 LTS" as
+	// image release, so accept a match on either
+	if f.release != "" && !strings.Contains(instance.Release, f.release) && !strings.Contains(instance.ImageRelease, f.release) {
+		return false
+	}
+
+	if f.hasIPv4 != nil && (len(instance.IPv4) > 0) != *f.hasIPv4 {
+		return false
+	}
+
+	return true
+}
+
+// filterInstances returns the instances matching any of the filters, or all
+// instances when there are none
+func filterInstances(instances []common.MultipassInstance, filters []instanceFilter) []common.MultipassInstance {
+	if len(filters) == 0 {
+		return instances
+	}
+
+	var filtered []common.MultipassInstance
+	for _, instance := range instances {
+		for _, filter := range filters {
+			if filter.matches(instance) {
+				filtered = append(filtered, instance)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// sortInstances orders instances by the given key, breaking ties by name
+func sortInstances(instances []common.MultipassInstance, sortBy string, descending bool) {
+	key := func(instance common.MultipassInstance) string {
+		switch sortBy {
+		case "state":
+			return instance.State.String()
+		case "release":
+			return instance.Release
+		default:
+			return instance.Name
+		}
+	}
+
+	sort.SliceStable(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if descending {
+			a, b = b, a
+		}
+		if key(a) != key(b) {
+			return key(a) < key(b)
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+var filterTestInstances = []common.MultipassInstance{
+	{Name: "web-2", State: "Running", IPv4: []string{"10.0.0.3"}, Release: "Ubuntu 22.04.3 LTS"},
+	{Name: "db-1", State: "Stopped", Release: "Ubuntu 20.04.6 LTS"},
+	{Name: "web-1", State: "Running", IPv4: []string{"10.0.0.2"}, Release: "Ubuntu 24.04 LTS"},
+	{Name: "web-3", State: "Stopped", ImageRelease: "22.04 LTS"},
+}
+
+func instanceNames(instances []common.MultipassInstance) []string {
+	names := []string{}
+	for _, instance := range instances {
+		names = append(names, instance.Name)
+	}
+	return names
+}
+
+func TestFilterInstances(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []InstanceFilterModel
+		want    []string
+	}{
+		{
+			name: "No filters",
+			want: []string{"web-2", "db-1", "web-1", "web-3"},
+		},
+		{
+			name:    "Glob",
+			filters: []InstanceFilterModel{{Name: types.StringValue("web-*")}},
+			want:    []string{"web-2", "web-1", "web-3"},
+		},
+		{
+			name:    "Regex",
+			filters: []InstanceFilterModel{{NameRegex: types.StringValue(`-[12]$`)}},
+			want:    []string{"web-2", "db-1", "web-1"},
+		},
+		{
+			name: "Glob and states",
+			filters: []InstanceFilterModel{{
+				Name:   types.StringValue("web-*"),
+				States: []types.String{types.StringValue("running")},
+			}},
+			want: []string{"web-2", "web-1"},
+		},
+		{
+			name:    "Release matches release or image release",
+			filters: []InstanceFilterModel{{Release: types.StringValue("22.04")}},
+			want:    []string{"web-2", "web-3"},
+		},
+		{
+			name:    "Without IPv4",
+			filters: []InstanceFilterModel{{HasIPv4: types.BoolValue(false)}},
+			want:    []string{"db-1", "web-3"},
+		},
+		{
+			name: "Any filter block matches",
+			filters: []InstanceFilterModel{
+				{Name: types.StringValue("db-*")},
+				{Name: types.StringValue("web-1")},
+			},
+			want: []string{"db-1", "web-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := compileInstanceFilters(tt.filters)
+			if err != nil {
+				t.Fatalf("compileInstanceFilters() error = %v", err)
+			}
+
+			got := instanceNames(filterInstances(filterTestInstances, filters))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterInstances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileInstanceFiltersInvalid(t *testing.T) {
+	if _, err := compileInstanceFilters([]InstanceFilterModel{{NameRegex: types.StringValue("web-(")}}); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+	if _, err := compileInstanceFilters([]InstanceFilterModel{{Name: types.StringValue("web-[")}}); err == nil {
+		t.Error("expected error for invalid glob")
+	}
+}
+
+func TestSortInstances(t *testing.T) {
+	tests := []struct {
+		sortBy     string
+		descending bool
+		want       []string
+	}{
+		{"", false, []string{"db-1", "web-1", "web-2", "web-3"}},
+		{"name", true, []string{"web-3", "web-2", "web-1", "db-1"}},
+		{"state", false, []string{"web-1", "web-2", "db-1", "web-3"}},
+		{"release", true, []string{"web-1", "web-2", "db-1", "web-3"}},
+	}
+
+	for _, tt := range tests {
+		instances := append([]common.MultipassInstance(nil), filterTestInstances...)
+		sortInstances(instances, tt.sortBy, tt.descending)
+
+		if got := instanceNames(instances); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sortInstances(%q, %v) = %v, want %v", tt.sortBy, tt.descending, got, tt.want)
+		}
+	}
+}
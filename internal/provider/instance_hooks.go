@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Lifecycle hooks of an instance
+const (
+	hookOnCreate      = "on_create"
+	hookBeforeStop    = "before_stop"
+	hookBeforeDestroy = "before_destroy"
+)
+
+// defaultHookTimeout bounds all commands of a hook when it sets no timeout
+const defaultHookTimeout = 5 * time.Minute
+
+// HookModel describes a lifecycle hook block.
+type HookModel struct {
+	Commands    []types.String `tfsdk:"commands"`
+	Timeout     types.String   `tfsdk:"timeout"`
+	FailOnError types.Bool     `tfsdk:"fail_on_error"`
+}
+
+// hookBlock returns the schema of a lifecycle hook block
+func hookBlock(description string) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: description + " Commands run in order with `sh -c` as the default user through `multipass exec`, and their output is logged.",
+		Attributes: map[string]schema.Attribute{
+			"commands": schema.ListAttribute{
+				MarkdownDescription: "Shell commands to run inside the instance",
+				Required:            true,
+				ElementType:         types.StringType,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Time allowed for all commands of the hook (e.g. '30s', '2m'). Defaults to 5m.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringValueValidator{label: "hook timeout", validate: validateTimeoutValue},
+				},
+			},
+			"fail_on_error": schema.BoolAttribute{
+				MarkdownDescription: "Whether a failing command fails the operation. When `false`, the failure is reported as a warning and the operation continues. Defaults to `true`.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// failOnError reports whether a failure of the hook fails the operation
+func (h *HookModel) failOnError() bool {
+	return h.FailOnError.IsNull() || h.FailOnError.IsUnknown() || h.FailOnError.ValueBool()
+}
+
+// timeout returns the configured hook timeout, or the default
+func (h *HookModel) timeout() time.Duration {
+	if h.Timeout.IsNull() || h.Timeout.IsUnknown() {
+		return defaultHookTimeout
+	}
+	timeout, err := time.ParseDuration(h.Timeout.ValueString())
+	if err != nil {
+		return defaultHookTimeout
+	}
+	return timeout
+}
+
+// runHook runs the commands of a hook inside an instance, stopping at the
+// first failing command
+func (r *InstanceResource) runHook(ctx context.Context, name string, hookName string, hook *HookModel) error {
+	if hook == nil || len(hook.Commands) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hook.timeout())
+	defer cancel()
+
+	for i, command := range hook.Commands {
+		fields := map[string]interface{}{
+			"name":    name,
+			"hook":    hookName,
+			"command": command.ValueString(),
+			"index":   i,
+		}
+		tflog.Info(ctx, "running multipass instance hook command", fields)
+
+		output, err := r.client.Exec(ctx, name, "sh", "-c", command.ValueString())
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("%s hook timed out after %s running command %d (%q): %w", hookName, hook.timeout(), i+1, command.ValueString(), err)
+			}
+			return fmt.Errorf("%s hook command %d (%q) failed: %w", hookName, i+1, command.ValueString(), err)
+		}
+
+		fields["output"] = strings.TrimSpace(output)
+		tflog.Info(ctx, "multipass instance hook command finished", fields)
+	}
+
+	return nil
+}
+
+// runHookDiagnostics runs a hook and reports its failure as an error or,
+// without fail_on_error, as a warning
+func (r *InstanceResource) runHookDiagnostics(ctx context.Context, name string, hookName string, hook *HookModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	err := r.runHook(ctx, name, hookName, hook)
+	if err == nil {
+		return diags
+	}
+
+	if hook.failOnError() {
+		diags.AddAttributeError(path.Root(hookName), "Instance Hook Failed",
+			fmt.Sprintf("Instance %q: %s", name, err))
+	} else {
+		diags.AddAttributeWarning(path.Root(hookName), "Instance Hook Failed",
+			fmt.Sprintf("Instance %q: %s. Continuing since fail_on_error is false.", name, err))
+	}
+
+	return diags
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeHookBinary creates a fake multipass binary whose exec fails for
+// commands containing "false"
+func writeHookBinary(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, `if [ "$1" = "exec" ]; then
+  case "$*" in
+    *false*)
+      echo 'command failed' >&2
+      exit 1
+      ;;
+  esac
+  echo 'done'
+fi
+exit 0
+`)
+}
+
+// testHook returns a hook running commands
+func testHook(commands ...string) *HookModel {
+	hook := &HookModel{Timeout: types.StringNull(), FailOnError: types.BoolNull()}
+	for _, command := range commands {
+		hook.Commands = append(hook.Commands, types.StringValue(command))
+	}
+	return hook
+}
+
+func TestRunHook(t *testing.T) {
+	binary, calls := writeHookBinary(t)
+	r := &InstanceResource{client: NewMultipassClient(binary)}
+
+	err := r.runHook(context.Background(), "web", hookOnCreate, testHook("apt-get update", "false", "never run"))
+	if err == nil || !strings.Contains(err.Error(), `on_create hook command 2 ("false") failed`) {
+		t.Errorf("runHook() error = %v", err)
+	}
+
+	// Commands stop at the first failure
+	want := []string{"exec web -- sh -c apt-get update", "exec web -- sh -c false"}
+	if got := readCalls(t, calls); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected calls %q, want %q", got, want)
+	}
+}
+
+func TestRunHookDiagnostics(t *testing.T) {
+	binary, _ := writeHookBinary(t)
+	r := &InstanceResource{client: NewMultipassClient(binary)}
+	ctx := context.Background()
+
+	if diags := r.runHookDiagnostics(ctx, "web", hookBeforeDestroy, nil); diags.HasError() || diags.WarningsCount() != 0 {
+		t.Errorf("Expected no diagnostics without a hook, got %v", diags)
+	}
+
+	hook := testHook("false")
+	if diags := r.runHookDiagnostics(ctx, "web", hookBeforeDestroy, hook); !diags.HasError() {
+		t.Error("Expected a failing hook to fail by default")
+	}
+
+	hook.FailOnError = types.BoolValue(false)
+	diags := r.runHookDiagnostics(ctx, "web", hookBeforeDestroy, hook)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("Expected a warning without fail_on_error, got %v", diags)
+	}
+}
+
+func TestStopInstanceBeforeStop(t *testing.T) {
+	tests := []struct {
+		name  string
+		state common.InstanceState
+		want  []string
+	}{
+		{
+			name:  "running",
+			state: common.StateRunning,
+			want:  []string{"exec web -- sh -c systemctl stop app", "stop web"},
+		},
+		{
+			name:  "stopped",
+			state: common.StateStopped,
+			want:  []string{"stop web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binary, calls := writeHookBinary(t)
+			r := &InstanceResource{client: NewMultipassClient(binary)}
+
+			data := testInstanceModel("web")
+			data.BeforeStop = testHook("systemctl stop app")
+
+			if diags := r.stopInstance(context.Background(), &data, tt.state); diags.HasError() {
+				t.Fatalf("stopInstance() diagnostics = %v", diags)
+			}
+			if got := readCalls(t, calls); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unexpected calls %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	hook := testHook("true")
+	if got := hook.timeout(); got != defaultHookTimeout {
+		t.Errorf("timeout() = %s, want %s", got, defaultHookTimeout)
+	}
+
+	hook.Timeout = types.StringValue("30s")
+	if got := hook.timeout().String(); got != "30s" {
+		t.Errorf("timeout() = %s, want 30s", got)
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithIdentity = &InstanceResource{}
+
+// InstanceIdentityModel describes the identity of an instance resource.
+type InstanceIdentityModel struct {
+	Name types.String `tfsdk:"name"`
+	Host types.String `tfsdk:"host"`
+}
+
+func (r *InstanceResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				Description:       "Instance name",
+				RequiredForImport: true,
+			},
+			"host": identityschema.StringAttribute{
+				Description:       "Hostname of the machine running multipass. Recorded when the instance is created or imported.",
+				OptionalForImport: true,
+			},
+		},
+	}
+}
+
+// multipassHost returns the hostname recorded in instance identities, or
+// null when it cannot be determined
+func multipassHost() types.String {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(hostname)
+}
+
+// setInstanceIdentity stores the instance name in the identity, keeping a
+// host that was already recorded so the identity does not change if the
+// machine is renamed
+func setInstanceIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if identity == nil {
+		return diags
+	}
+
+	data := InstanceIdentityModel{Host: types.StringNull()}
+	if !identity.Raw.IsNull() {
+		diags.Append(identity.Get(ctx, &data)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	data.Name = types.StringValue(name)
+	if data.Host.IsNull() || data.Host.IsUnknown() {
+		data.Host = multipassHost()
+	}
+
+	diags.Append(identity.Set(ctx, &data)...)
+	return diags
+}
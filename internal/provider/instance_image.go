@@ -0,0 +1,380 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// imageFilePrivateKey is the private state key holding the size and
+// modification time of a file:// image when it was last hashed
+const imageFilePrivateKey = "image_file"
+
+// imageFileStamp identifies a version of a file:// image without reading it
+type imageFileStamp struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mod_time"`
+}
+
+// statImageFile returns the stamp of a file:// image
+func statImageFile(image string) (*imageFileStamp, error) {
+	path := strings.TrimPrefix(image, imageFilePrefix)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("image path %s is a directory, not a file", path)
+	}
+
+	return &imageFileStamp{Path: path, Size: info.Size(), ModTime: info.ModTime().UnixNano()}, nil
+}
+
+// parseImageFileStamp returns the stamp recorded in private state, or nil
+func parseImageFileStamp(raw []byte) *imageFileStamp {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var stamp imageFileStamp
+	if err := json.Unmarshal(raw, &stamp); err != nil {
+		return nil
+	}
+	return &stamp
+}
+
+// imageFileStampData returns the private state value recording the stamp
+// of a hashed file:// image, so that plans only hash the file again once it
+// changes. It is empty for other images, which removes the key.
+func imageFileStampData(image types.String) []byte {
+	if !strings.HasPrefix(image.ValueString(), imageFilePrefix) {
+		return nil
+	}
+
+	stamp, err := statImageFile(image.ValueString())
+	if err != nil {
+		return nil
+	}
+
+	data, err := json.Marshal(stamp)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// fileSHA256 returns the hex sha256 digest of a file:// image
+func fileSHA256(image string) (string, error) {
+	file, err := os.Open(strings.TrimPrefix(image, imageFilePrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checkImageDigest compares a hex sha256 digest with a checksum given as
+// "<hex>" or "sha256:<hex>"
+func checkImageDigest(digest string, checksum string) error {
+	expected := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	if digest != expected {
+		return fmt.Errorf("checksum mismatch, expected sha256:%s but the image has sha256:%s", expected, digest)
+	}
+	return nil
+}
+
+// verifyImageChecksum checks a file:// image against a sha256 checksum
+func verifyImageChecksum(ctx context.Context, image string, checksum string) error {
+	tflog.Info(ctx, "verifying multipass image checksum", map[string]interface{}{"image": image})
+
+	digest, err := fileSHA256(image)
+	if err != nil {
+		return err
+	}
+
+	return checkImageDigest(digest, checksum)
+}
+
+// multipassReadableDir returns a directory for temporary files that
+// multipass reads. On Linux, multipass is usually a snap, which has a
+// private /tmp and can only read non-hidden files in the home directory.
+func multipassReadableDir() (string, error) {
+	if runtime.GOOS != "linux" {
+		return os.TempDir(), nil
+	}
+	return os.UserHomeDir()
+}
+
+// downloadImage downloads an https:// image once and verifies it against a
+// sha256 checksum, returning the verified copy as a file:// image so that
+// multipass launches exactly the bytes that were checked. cleanup removes
+// the copy.
+func downloadImage(ctx context.Context, image string, checksum string) (string, func(), error) {
+	tflog.Info(ctx, "downloading multipass image for checksum verification", map[string]interface{}{"image": image})
+
+	dir, err := multipassReadableDir()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find a directory for the image download: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(dir, "multipass-image-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create image download directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	// multipass recognises the image format by its extension
+	name := "image.img"
+	if parsed, err := url.Parse(image); err == nil && strings.Trim(parsed.Path, "/") != "" {
+		name = filepath.Base(parsed.Path)
+	}
+
+	local, err := fetchImage(ctx, image, filepath.Join(tempDir, name), checksum)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return local, cleanup, nil
+}
+
+// fetchImage streams an https:// image to target while hashing it
+func fetchImage(ctx context.Context, image string, target string, checksum string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, image, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to request image: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download image: %s", resp.Status)
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create image file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), resp.Body); err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write image file: %w", err)
+	}
+
+	if err := checkImageDigest(hex.EncodeToString(hash.Sum(nil)), checksum); err != nil {
+		return "", err
+	}
+
+	return imageFilePrefix + target, nil
+}
+
+// localImageHash returns the sha256 digest of a file:// image, null for any
+// other image and unknown while the image is unknown
+func localImageHash(ctx context.Context, image types.String) (types.String, error) {
+	if image.IsUnknown() {
+		return types.StringUnknown(), nil
+	}
+	if image.IsNull() || !strings.HasPrefix(image.ValueString(), imageFilePrefix) {
+		return types.StringNull(), nil
+	}
+
+	tflog.Info(ctx, "hashing multipass image file", map[string]interface{}{"image": image.ValueString()})
+
+	digest, err := fileSHA256(image.ValueString())
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	return types.StringValue(digest), nil
+}
+
+// planImageHash plans the image_hash of an instance. A file:// image is
+// hashed when a new instance is planned, and for an existing instance only
+// once the file differs from the recorded stamp, so that the instance is
+// replaced when the file changes. A file that is gone no longer matters to
+// the instance launched from it. The hash multipass reports for other images
+// is kept once known, so that plans stay stable when an alias such as "lts"
+// moves to a newer image. A nil state plans a new instance.
+func planImageHash(ctx context.Context, plan *InstanceResourceModel, state *InstanceResourceModel, stamp *imageFileStamp, resp *resource.ModifyPlanResponse) {
+	image := plan.Image
+	local := !image.IsUnknown() && strings.HasPrefix(image.ValueString(), imageFilePrefix)
+
+	if state == nil {
+		plan.ImageHash = types.StringUnknown()
+		if !local {
+			// Multipass reports the hash of other images after the launch
+			return
+		}
+
+		hash, err := localImageHash(ctx, image)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("image"), "Unable to Hash Image",
+				fmt.Sprintf("Unable to read image %q, got error: %s", image.ValueString(), err))
+			return
+		}
+		plan.ImageHash = hash
+		return
+	}
+
+	if !image.Equal(state.Image) {
+		// A different image replaces the instance, whose new hash is
+		// planned along with it
+		plan.ImageHash = types.StringUnknown()
+		return
+	}
+
+	if !local {
+		plan.ImageHash = state.ImageHash
+		if state.ImageHash.IsNull() {
+			// States written before image_hash existed record it on the
+			// next apply rather than replacing the instance
+			plan.ImageHash = types.StringUnknown()
+		}
+		return
+	}
+
+	plan.ImageHash = state.ImageHash
+
+	current, err := statImageFile(image.ValueString())
+	if err != nil {
+		tflog.Debug(ctx, "keeping image_hash of an instance whose image file cannot be read", map[string]interface{}{
+			"image": image.ValueString(),
+			"error": err.Error(),
+		})
+		return
+	}
+	if state.ImageHash.IsNull() {
+		// Recorded on the next apply, as for other images
+		plan.ImageHash = types.StringUnknown()
+		return
+	}
+	if stamp != nil && *stamp == *current {
+		return
+	}
+
+	hash, err := localImageHash(ctx, image)
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(path.Root("image"), "Unable to Hash Image",
+			fmt.Sprintf("Unable to read image %q to check it for changes, got error: %s", image.ValueString(), err))
+		return
+	}
+
+	plan.ImageHash = hash
+	if !hash.Equal(state.ImageHash) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("image_hash"))
+	}
+}
+
+// recordImageDetails fills in the release and, for images other than
+// file:// ones, the image_hash reported by multipass when they are not known
+// yet. Known values are kept so that plans stay stable.
+func recordImageDetails(data *InstanceResourceModel, instance *common.MultipassInstance) {
+	if data.Release.IsUnknown() || data.Release.IsNull() {
+		data.Release = stringOrNull(instance.Release)
+	}
+
+	if strings.HasPrefix(data.Image.ValueString(), imageFilePrefix) {
+		return
+	}
+	if data.ImageHash.IsUnknown() || data.ImageHash.IsNull() {
+		data.ImageHash = stringOrNull(instance.ImageHash)
+	}
+}
+
+// stringOrNull returns a string value, or null when it is empty
+func stringOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// checkImageRemote reports an image_remote without an image name to take
+// from it. Remotes serve catalog images, not URLs.
+func checkImageRemote(data InstanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	image := data.Image
+	if !data.ImageRemote.IsNull() && !image.IsUnknown() && (image.IsNull() || isImageURL(image.ValueString())) {
+		diags.AddAttributeError(path.Root("image_remote"), "Invalid Attribute Combination",
+			"image_remote requires image, or the image of the provider defaults, to be an image name such as 24.04.")
+	}
+
+	return diags
+}
+
+// checkImageAvailable reports a planned image or blueprint that multipass
+// find does not list. URLs and values that are not known yet are left to
+// the launch.
+func (r *InstanceResource) checkImageAvailable(ctx context.Context, plan InstanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.Blueprint.IsUnknown() || plan.Image.IsUnknown() || plan.ImageRemote.IsUnknown() {
+		return diags
+	}
+	if plan.Blueprint.IsNull() && (plan.Image.IsNull() || isImageURL(plan.Image.ValueString())) {
+		return diags
+	}
+
+	remote := plan.ImageRemote.ValueString()
+	found, err := r.client.FindImages(ctx, remote)
+	if err != nil {
+		tflog.Warn(ctx, "skipping image check", map[string]interface{}{
+			"error": err.Error(),
+		})
+		diags.AddWarning("Unable to Check Image",
+			fmt.Sprintf("The planned image could not be looked up with multipass find: %s", err))
+		return diags
+	}
+
+	if !plan.Blueprint.IsNull() {
+		blueprint := plan.Blueprint.ValueString()
+		if !hasBlueprint(found, blueprint) {
+			diags.AddAttributeError(path.Root("blueprint"), "Blueprint Not Found",
+				fmt.Sprintf("Blueprint %q is not listed by `multipass find`.", blueprint))
+		}
+		return diags
+	}
+
+	image := plan.Image.ValueString()
+	if !hasImage(found, remote, image) {
+		command := "multipass find"
+		if remote != "" {
+			command += " " + remote + ":"
+		}
+		diags.AddAttributeError(path.Root("image"), "Image Not Found",
+			fmt.Sprintf("Image %q is not listed by `%s`.", image, command))
+	}
+
+	return diags
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// writeImageFile creates a local image with the given content and returns
+// its file:// URL and sha256 digest
+func writeImageFile(t *testing.T, content string) (string, string) {
+	t.Helper()
+
+	image := filepath.Join(t.TempDir(), "custom.img")
+	if err := os.WriteFile(image, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create image file: %v", err)
+	}
+
+	digest := sha256.Sum256([]byte(content))
+	return "file://" + image, hex.EncodeToString(digest[:])
+}
+
+func TestVerifyImageChecksumFile(t *testing.T) {
+	image, digest := writeImageFile(t, "custom image")
+	ctx := context.Background()
+
+	if err := verifyImageChecksum(ctx, image, "sha256:"+strings.ToUpper(digest)); err != nil {
+		t.Errorf("verifyImageChecksum() unexpected error: %v", err)
+	}
+
+	err := verifyImageChecksum(ctx, image, strings.Repeat("0", 64))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestDownloadImage(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/custom.img" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("custom image"))
+	}))
+	defer server.Close()
+
+	// Trust the test server certificate
+	client := http.DefaultClient
+	http.DefaultClient = server.Client()
+	defer func() { http.DefaultClient = client }()
+
+	// Downloads go to the home directory on Linux
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	digest := sha256.Sum256([]byte("custom image"))
+	ctx := context.Background()
+
+	image, cleanup, err := downloadImage(ctx, server.URL+"/custom.img", "sha256:"+hex.EncodeToString(digest[:]))
+	if err != nil {
+		t.Fatalf("downloadImage() unexpected error: %v", err)
+	}
+
+	// The verified copy is what gets launched
+	content, err := os.ReadFile(strings.TrimPrefix(image, "file://"))
+	if err != nil || string(content) != "custom image" || filepath.Base(image) != "custom.img" {
+		t.Errorf("Expected the verified copy at a file:// image, got %s with %q (%v)", image, content, err)
+	}
+	cleanup()
+	if _, err := os.Stat(strings.TrimPrefix(image, "file://")); !os.IsNotExist(err) {
+		t.Errorf("Expected cleanup to remove the copy, got %v", err)
+	}
+
+	_, _, err = downloadImage(ctx, server.URL+"/custom.img", strings.Repeat("0", 64))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	_, _, err = downloadImage(ctx, server.URL+"/missing.img", hex.EncodeToString(digest[:]))
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a download error, got %v", err)
+	}
+
+	// Failed downloads leave nothing behind
+	if runtime.GOOS == "linux" {
+		if entries, _ := os.ReadDir(home); len(entries) != 0 {
+			t.Errorf("Expected failed downloads to be removed, got %v", entries)
+		}
+	}
+}
+
+func TestModifyPlanImageHash(t *testing.T) {
+	image, digest := writeImageFile(t, "rebuilt image")
+
+	prior := testInstanceModel("web")
+	prior.Image = types.StringValue(image)
+	prior.ImageHash = types.StringValue(strings.Repeat("0", 64))
+	planned := prior
+	planned.ImageHash = types.StringUnknown()
+
+	resp := modifyInstancePlan(t, &prior, &planned)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected errors: %v", resp.Diagnostics)
+	}
+
+	var hash types.String
+	resp.Plan.GetAttribute(context.Background(), path.Root("image_hash"), &hash)
+	if hash.ValueString() != digest {
+		t.Errorf("Expected image_hash %s in the plan, got %s", digest, hash)
+	}
+
+	if len(resp.RequiresReplace) != 1 || !resp.RequiresReplace[0].Equal(path.Root("image_hash")) {
+		t.Errorf("Expected a changed image file to require replacement, got %v", resp.RequiresReplace)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || !strings.Contains(resp.Diagnostics.Warnings()[0].Detail(), "image_hash") {
+		t.Errorf("Expected a replacement warning naming image_hash, got %v", resp.Diagnostics)
+	}
+}
+
+func TestModifyPlanImageHashRecorded(t *testing.T) {
+	image, _ := writeImageFile(t, "custom image")
+
+	// A state written before image_hash existed
+	prior := testInstanceModel("web")
+	prior.Image = types.StringValue(image)
+	planned := prior
+	planned.ImageHash = types.StringUnknown()
+
+	resp := modifyInstancePlan(t, &prior, &planned)
+	if len(resp.Diagnostics) != 0 || len(resp.RequiresReplace) != 0 {
+		t.Errorf("Expected an in-place update, got %v and replacement of %v", resp.Diagnostics, resp.RequiresReplace)
+	}
+}
+
+func TestModifyPlanImageHashCatalogImage(t *testing.T) {
+	prior := testInstanceModel("web")
+	prior.ImageHash = types.StringValue("abc123")
+	prior.Release = types.StringValue("Ubuntu 22.04 LTS")
+	planned := prior
+	planned.ImageHash = types.StringUnknown()
+	planned.DeletionProtection = types.BoolValue(true)
+
+	resp := modifyInstancePlan(t, &prior, &planned)
+	if len(resp.Diagnostics) != 0 || len(resp.RequiresReplace) != 0 {
+		t.Fatalf("Expected an in-place update, got %v and replacement of %v", resp.Diagnostics, resp.RequiresReplace)
+	}
+
+	// The hash multipass reported at launch is kept
+	var hash types.String
+	resp.Plan.GetAttribute(context.Background(), path.Root("image_hash"), &hash)
+	if hash.ValueString() != "abc123" {
+		t.Errorf("Expected image_hash abc123 in the plan, got %s", hash)
+	}
+}
+
+func TestPlanImageHashUnchangedFile(t *testing.T) {
+	image, _ := writeImageFile(t, "rebuilt image")
+	stamp, err := statImageFile(image)
+	if err != nil {
+		t.Fatalf("statImageFile() error = %v", err)
+	}
+
+	state := testInstanceModel("web")
+	state.Image = types.StringValue(image)
+	state.ImageHash = types.StringValue(strings.Repeat("0", 64))
+
+	// A file matching the recorded stamp is not read again
+	plan := state
+	resp := &resource.ModifyPlanResponse{}
+	planImageHash(context.Background(), &plan, &state, stamp, resp)
+	if len(resp.Diagnostics) != 0 || len(resp.RequiresReplace) != 0 || !plan.ImageHash.Equal(state.ImageHash) {
+		t.Errorf("Expected the recorded hash to be kept, got %s with %v and replacement of %v", plan.ImageHash, resp.Diagnostics, resp.RequiresReplace)
+	}
+
+	// Once the stamp differs, the file is hashed again
+	changed := *stamp
+	changed.ModTime--
+	resp = &resource.ModifyPlanResponse{}
+	planImageHash(context.Background(), &plan, &state, &changed, resp)
+	if len(resp.RequiresReplace) != 1 {
+		t.Errorf("Expected a changed file to require replacement, got %v", resp.RequiresReplace)
+	}
+}
+
+func TestModifyPlanImageHashMissingFile(t *testing.T) {
+	image := "file://" + filepath.Join(t.TempDir(), "removed.img")
+
+	prior := testInstanceModel("web")
+	prior.Image = types.StringValue(image)
+	prior.ImageHash = types.StringValue(strings.Repeat("0", 64))
+	planned := prior
+	planned.ImageHash = types.StringUnknown()
+
+	// The instance no longer depends on the file it was launched from
+	resp := modifyInstancePlan(t, &prior, &planned)
+	if len(resp.Diagnostics) != 0 || len(resp.RequiresReplace) != 0 {
+		t.Fatalf("Expected an in-place update, got %v and replacement of %v", resp.Diagnostics, resp.RequiresReplace)
+	}
+
+	var hash types.String
+	resp.Plan.GetAttribute(context.Background(), path.Root("image_hash"), &hash)
+	if !hash.Equal(prior.ImageHash) {
+		t.Errorf("Expected the recorded image_hash to be kept, got %s", hash)
+	}
+
+	// Destroying it needs no file either
+	if diags := runInstanceModifyPlan(t, &prior, nil); len(diags) != 0 {
+		t.Errorf("Expected no diagnostics when destroying, got %v", diags)
+	}
+}
+
+func TestImageFileStampData(t *testing.T) {
+	image, _ := writeImageFile(t, "custom image")
+
+	stamp := parseImageFileStamp(imageFileStampData(types.StringValue(image)))
+	if stamp == nil || stamp.Size != int64(len("custom image")) {
+		t.Errorf("Expected the stamp of the image file, got %+v", stamp)
+	}
+	if data := imageFileStampData(types.StringValue("22.04")); data != nil {
+		t.Errorf("Expected no stamp for a catalog image, got %s", data)
+	}
+}
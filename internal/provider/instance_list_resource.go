@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &InstanceListResource{}
+var _ list.ListResourceWithConfigure = &InstanceListResource{}
+
+func NewInstanceListResource() list.ListResource {
+	return &InstanceListResource{}
+}
+
+// InstanceListResource lists existing instances for `terraform query`.
+type InstanceListResource struct {
+	client *MultipassClient
+}
+
+// InstanceListResourceModel describes the list block configuration.
+type InstanceListResourceModel struct {
+	Filters []InstanceFilterModel `tfsdk:"filter"`
+}
+
+func (r *InstanceListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance"
+}
+
+func (r *InstanceListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Multipass instances on this machine so `terraform query` can generate import blocks for them. Requires Terraform 1.14 or later.",
+		Blocks: map[string]schema.Block{
+			"filter": listFilterBlock(instanceFilterDescription),
+		},
+	}
+}
+
+// listFilterBlock builds the filter block from instanceFilterAttributes
+func listFilterBlock(description string) schema.ListNestedBlock {
+	attributes := make(map[string]schema.Attribute)
+	for name, filter := range instanceFilterAttributes() {
+		switch {
+		case filter.valueType.Equal(types.StringType):
+			attributes[name] = schema.StringAttribute{MarkdownDescription: filter.description, Optional: true, Validators: filter.validators}
+		case filter.valueType.Equal(types.BoolType):
+			attributes[name] = schema.BoolAttribute{MarkdownDescription: filter.description, Optional: true}
+		default:
+			attributes[name] = schema.ListAttribute{MarkdownDescription: filter.description, Optional: true, ElementType: filter.valueType.(types.ListType).ElemType}
+		}
+	}
+
+	return schema.ListNestedBlock{
+		MarkdownDescription: description,
+		NestedObject:        schema.NestedBlockObject{Attributes: attributes},
+	}
+}
+
+func (r *InstanceListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*MultipassClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *MultipassClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *InstanceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data InstanceListResourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	filters, err := compileInstanceFilters(data.Filters)
+	if err != nil {
+		diags.AddAttributeError(path.Root("filter"), "Invalid Filter", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	tflog.Trace(ctx, "listing multipass instances for query")
+
+	instances, err := r.client.ListInstances(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list instances, got error: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	instances = filterInstances(instances, filters)
+	sortInstances(instances, "name", false)
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, instance := range instances {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+			if !push(r.listResult(ctx, req, instance)) {
+				return
+			}
+		}
+	}
+}
+
+// listResult converts an instance to a list result with its identity and,
+// when requested, its resource state
+func (r *InstanceListResource) listResult(ctx context.Context, req list.ListRequest, instance common.MultipassInstance) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = instance.Name
+
+	result.Diagnostics.Append(setInstanceIdentity(ctx, result.Identity, instance.Name)...)
+
+	if req.IncludeResource {
+		ipv4 := instance.IPv4
+		if ipv4 == nil {
+			ipv4 = []string{}
+		}
+
+		result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("id"), instance.Name)...)
+		result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("name"), instance.Name)...)
+		result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("state"), instance.State.String())...)
+		result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("ipv4"), ipv4)...)
+	}
+
+	return result
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dataschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// writeListBinary creates a fake multipass binary answering `list`
+func writeListBinary(t *testing.T) string {
+	t.Helper()
+
+	binary, _ := writeFakeMultipass(t, `if [ "$1" = "list" ]; then
+  echo '{"list": [{"name": "web-2", "state": "Running", "ipv4": ["10.0.0.3"], "release": "22.04 LTS"}, {"name": "db-1", "state": "Stopped", "ipv4": [], "release": "22.04 LTS"}, {"name": "web-1", "state": "Stopped", "ipv4": [], "release": "20.04 LTS"}]}'
+  exit 0
+fi
+exit 2
+`)
+	return binary
+}
+
+// newListRequest builds a list request for the instance list resource with
+// the given filter block values
+func newListRequest(t *testing.T, filters []tftypes.Value, includeResource bool) list.ListRequest {
+	t.Helper()
+
+	ctx := context.Background()
+	r := &InstanceResource{}
+	lr := &InstanceListResource{}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	identityResp := &resource.IdentitySchemaResponse{}
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identityResp)
+
+	configResp := &list.ListResourceSchemaResponse{}
+	lr.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, configResp)
+
+	configType := configResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	filterType := configType.AttributeTypes["filter"].(tftypes.List)
+
+	var filterValue tftypes.Value
+	if filters == nil {
+		filterValue = tftypes.NewValue(filterType, nil)
+	} else {
+		filterValue = tftypes.NewValue(filterType, filters)
+	}
+
+	return list.ListRequest{
+		Config: tfsdk.Config{
+			Schema: configResp.Schema,
+			Raw:    tftypes.NewValue(configType, map[string]tftypes.Value{"filter": filterValue}),
+		},
+		IncludeResource:        includeResource,
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: identityResp.IdentitySchema,
+	}
+}
+
+// collectListResults runs List and returns the streamed results
+func collectListResults(t *testing.T, lr *InstanceListResource, req list.ListRequest) []list.ListResult {
+	t.Helper()
+
+	stream := &list.ListResultsStream{}
+	lr.List(context.Background(), req, stream)
+
+	var results []list.ListResult
+	for result := range stream.Results {
+		if result.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", result.Diagnostics)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestInstanceListResource(t *testing.T) {
+	ctx := context.Background()
+	lr := &InstanceListResource{client: NewMultipassClient(writeListBinary(t))}
+
+	results := collectListResults(t, lr, newListRequest(t, nil, true))
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	wantNames := []string{"db-1", "web-1", "web-2"}
+	for i, result := range results {
+		if result.DisplayName != wantNames[i] {
+			t.Errorf("result %d display name = %q, want %q", i, result.DisplayName, wantNames[i])
+		}
+
+		var identity InstanceIdentityModel
+		if diags := result.Identity.Get(ctx, &identity); diags.HasError() {
+			t.Fatalf("Failed to read identity: %v", diags)
+		}
+		if identity.Name.ValueString() != wantNames[i] {
+			t.Errorf("result %d identity name = %s, want %q", i, identity.Name, wantNames[i])
+		}
+		if !identity.Host.Equal(multipassHost()) {
+			t.Errorf("result %d identity host = %s, want %s", i, identity.Host, multipassHost())
+		}
+	}
+
+	var state types.String
+	if diags := results[2].Resource.GetAttribute(ctx, path.Root("state"), &state); diags.HasError() {
+		t.Fatalf("Failed to read resource state: %v", diags)
+	}
+	if state.ValueString() != "Running" {
+		t.Errorf("web-2 state = %s, want Running", state)
+	}
+}
+
+func TestInstanceListResourceFilter(t *testing.T) {
+	lr := &InstanceListResource{client: NewMultipassClient(writeListBinary(t))}
+
+	req := newListRequest(t, nil, false)
+	filterType := req.Config.Raw.Type().(tftypes.Object).AttributeTypes["filter"].(tftypes.List).ElementType.(tftypes.Object)
+	filter := tftypes.NewValue(filterType, map[string]tftypes.Value{
+		"name":       tftypes.NewValue(tftypes.String, "web-*"),
+		"name_regex": tftypes.NewValue(tftypes.String, nil),
+		"states":     tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "stopped")}),
+		"release":    tftypes.NewValue(tftypes.String, nil),
+		"has_ipv4":   tftypes.NewValue(tftypes.Bool, nil),
+	})
+
+	results := collectListResults(t, lr, newListRequest(t, []tftypes.Value{filter}, false))
+	if len(results) != 1 || results[0].DisplayName != "web-1" {
+		t.Fatalf("expected only web-1, got %+v", results)
+	}
+
+	// Limits stop the stream early
+	req = newListRequest(t, nil, false)
+	req.Limit = 2
+	if results := collectListResults(t, lr, req); len(results) != 2 {
+		t.Errorf("expected 2 results with limit, got %d", len(results))
+	}
+}
+
+// TestInstanceListResourceFilterSchema tests that the filter block matches
+// the one of the instance data source, validators included
+func TestInstanceListResourceFilterSchema(t *testing.T) {
+	ctx := context.Background()
+
+	var listResp list.ListResourceSchemaResponse
+	(&InstanceListResource{}).ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &listResp)
+	var dataResp datasource.SchemaResponse
+	(&InstanceDataSource{}).Schema(ctx, datasource.SchemaRequest{}, &dataResp)
+
+	listFilter := listResp.Schema.Blocks["filter"].(listschema.ListNestedBlock).NestedObject.Attributes
+	dataFilter := dataResp.Schema.Blocks["filter"].(dataschema.ListNestedBlock).NestedObject.Attributes
+	if len(listFilter) != len(dataFilter) {
+		t.Fatalf("Expected %d filter attributes, got %d", len(dataFilter), len(listFilter))
+	}
+	for name, attribute := range dataFilter {
+		if other, ok := listFilter[name]; !ok || !other.GetType().Equal(attribute.GetType()) {
+			t.Errorf("Expected filter attribute %s of type %s, got %v", name, attribute.GetType(), other)
+		}
+	}
+
+	// An invalid regex is a config diagnostic rather than a List error
+	if validators := listFilter["name_regex"].(listschema.StringAttribute).Validators; len(validators) != 1 {
+		t.Errorf("Expected name_regex to be validated, got %d validators", len(validators))
+	}
+}
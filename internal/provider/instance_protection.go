@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// replacingAttributes are the instance attributes marked RequiresReplace,
+// plus image_hash which plan modification compares itself. Resource level
+// plan modification runs without the replacement paths found by attribute
+// plan modifiers, so changes to these are detected here.
+var replacingAttributes = []string{"name", "image", "image_hash", "image_remote", "blueprint", "cpu", "memory", "disk", "cloud_init"}
+
+// deletionProtectedDetail explains how to destroy a protected instance
+func deletionProtectedDetail(name string) string {
+	return fmt.Sprintf("Instance %q has deletion_protection enabled. "+
+		"Set deletion_protection = false and apply before destroying or replacing it.", name)
+}
+
+// checkDestroyProtection fails destroy plans of instances whose state has
+// deletion_protection enabled
+func checkDestroyProtection(ctx context.Context, state tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var data InstanceResourceModel
+
+	diags.Append(state.Get(ctx, &data)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if data.DeletionProtection.ValueBool() {
+		diags.AddError("Instance Deletion Protected", deletionProtectedDetail(data.Name.ValueString()))
+	}
+
+	return diags
+}
+
+// checkReplacement warns about each attribute forcing the instance to be
+// replaced, and fails the plan when the instance is protected
+func checkReplacement(ctx context.Context, state tfsdk.State, plan tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var changes []string
+	renamed := false
+
+	for _, attribute := range replacingAttributes {
+		var prior, planned types.String
+
+		diags.Append(state.GetAttribute(ctx, path.Root(attribute), &prior)...)
+		diags.Append(plan.GetAttribute(ctx, path.Root(attribute), &planned)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		// A value that is only now being recorded replaces nothing
+		if prior.IsNull() && planned.IsUnknown() {
+			continue
+		}
+
+		if !prior.Equal(planned) {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", attribute, describeValue(prior), describeValue(planned)))
+			renamed = renamed || attribute == "name"
+		}
+	}
+
+	// Blocks are compared as a whole
+	var priorConfig, plannedConfig types.Object
+
+	diags.Append(state.GetAttribute(ctx, path.Root("cloud_config"), &priorConfig)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("cloud_config"), &plannedConfig)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !priorConfig.Equal(plannedConfig) {
+		changes = append(changes, "cloud_config: block changed")
+	}
+
+	if len(changes) == 0 {
+		return diags
+	}
+
+	var data InstanceResourceModel
+
+	diags.Append(state.Get(ctx, &data)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	name := data.Name.ValueString()
+
+	detail := fmt.Sprintf("Instance %q will be destroyed and recreated, losing all data inside it, because of:\n  - %s",
+		name, strings.Join(changes, "\n  - "))
+
+	if data.DeletionProtection.ValueBool() {
+		diags.AddError("Instance Deletion Protected", detail+"\n\n"+deletionProtectedDetail(name))
+		return diags
+	}
+
+	// The destroyed instance is not purged, so the replacement could not
+	// be launched under its name
+	backup := data.SnapshotBeforeDestroy
+	if backup != nil && backup.backupMethod() == backupMethodSnapshot && !renamed {
+		diags.AddError("Instance Replacement Not Possible", detail+"\n\n"+
+			fmt.Sprintf("snapshot_before_destroy uses method = %q, which keeps the deleted instance %q unpurged, so its name stays taken. "+
+				"Set method = %q and apply before replacing it, or give the replacement a different name.", backupMethodSnapshot, name, backupMethodClone))
+		return diags
+	}
+
+	diags.AddWarning("Instance Will Be Replaced", detail)
+	return diags
+}
+
+// describeValue formats a string attribute value for plan diagnostics
+func describeValue(value types.String) string {
+	if value.IsUnknown() {
+		return "(known after apply)"
+	}
+	if value.IsNull() {
+		return "(default)"
+	}
+	return fmt.Sprintf("%q", value.ValueString())
+}
@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testInstanceModel returns the model of a created instance with the given name
+func testInstanceModel(name string) InstanceResourceModel {
+	data := InstanceResourceModel{
+		Id:                 types.StringValue(name),
+		Name:               types.StringValue(name),
+		Image:              types.StringValue("22.04"),
+		CPU:                types.StringNull(),
+		Memory:             types.StringValue("2G"),
+		Disk:               types.StringNull(),
+		MemoryBytes:        types.Int64Value(2 << 30),
+		DiskBytes:          types.Int64Null(),
+		CloudInit:          types.StringNull(),
+		State:              types.StringValue("Running"),
+		IPv4:               types.ListValueMust(types.StringType, []attr.Value{}),
+		OnCreateFailure:    types.StringNull(),
+		DeletionProtection: types.BoolNull(),
+		RestartTriggers:    types.MapNull(types.StringType),
+	}
+	data.Timeouts.Object = types.ObjectNull(map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	})
+	return data
+}
+
+// runInstanceModifyPlan runs ModifyPlan from prior to planned, where a nil
+// model stands for a missing state or a destroy plan
+func runInstanceModifyPlan(t *testing.T, prior *InstanceResourceModel, planned *InstanceResourceModel) diag.Diagnostics {
+	t.Helper()
+	return modifyInstancePlan(t, prior, planned).Diagnostics
+}
+
+// modifyInstancePlan runs ModifyPlan like runInstanceModifyPlan and returns the response
+func modifyInstancePlan(t *testing.T, prior *InstanceResourceModel, planned *InstanceResourceModel) *resource.ModifyPlanResponse {
+	t.Helper()
+	return modifyInstancePlanWithConfig(t, &InstanceResource{}, prior, planned, planned)
+}
+
+// modifyInstancePlanWithConfig runs ModifyPlan of r, where config is the
+// configuration the planned values were proposed from
+func modifyInstancePlanWithConfig(t *testing.T, r *InstanceResource, prior *InstanceResourceModel, planned *InstanceResourceModel, config *InstanceResourceModel) *resource.ModifyPlanResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	null := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+	req := resource.ModifyPlanRequest{
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: null},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: null},
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: null},
+	}
+	if prior != nil {
+		if diags := req.State.Set(ctx, prior); diags.HasError() {
+			t.Fatalf("Unable to build state: %v", diags)
+		}
+	}
+	if planned != nil {
+		if diags := req.Plan.Set(ctx, planned); diags.HasError() {
+			t.Fatalf("Unable to build plan: %v", diags)
+		}
+	}
+	if config != nil {
+		configured := tfsdk.State{Schema: schemaResp.Schema, Raw: null}
+		if diags := configured.Set(ctx, config); diags.HasError() {
+			t.Fatalf("Unable to build config: %v", diags)
+		}
+		req.Config.Raw = configured.Raw
+	}
+
+	resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, resp)
+
+	return resp
+}
+
+func TestModifyPlanReplacementWarning(t *testing.T) {
+	prior := testInstanceModel("web")
+	planned := testInstanceModel("web")
+	planned.Memory = types.StringValue("4G")
+	planned.CloudInit = types.StringValue("cloud-init.yaml")
+
+	diags := runInstanceModifyPlan(t, &prior, &planned)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("Expected a single warning, got %v", diags)
+	}
+
+	warning := diags.Warnings()[0]
+	if warning.Summary() != "Instance Will Be Replaced" {
+		t.Errorf("Unexpected warning summary %q", warning.Summary())
+	}
+	for _, want := range []string{`memory: "2G" -> "4G"`, `cloud_init: (default) -> "cloud-init.yaml"`} {
+		if !strings.Contains(warning.Detail(), want) {
+			t.Errorf("Expected warning detail to contain %q, got %q", want, warning.Detail())
+		}
+	}
+	if strings.Contains(warning.Detail(), "image") {
+		t.Errorf("Expected unchanged attributes to be left out, got %q", warning.Detail())
+	}
+}
+
+func TestModifyPlanInPlaceUpdate(t *testing.T) {
+	prior := testInstanceModel("web")
+	planned := testInstanceModel("web")
+	planned.DeletionProtection = types.BoolValue(true)
+
+	if diags := runInstanceModifyPlan(t, &prior, &planned); len(diags) != 0 {
+		t.Errorf("Expected no diagnostics for an in-place update, got %v", diags)
+	}
+}
+
+func TestModifyPlanDeletionProtection(t *testing.T) {
+	prior := testInstanceModel("web")
+	prior.DeletionProtection = types.BoolValue(true)
+
+	replaced := testInstanceModel("web")
+	replaced.DeletionProtection = types.BoolValue(false)
+	replaced.Memory = types.StringValue("4G")
+
+	testCases := []struct {
+		name    string
+		planned *InstanceResourceModel
+	}{
+		{"Destroy", nil},
+		{"Replace", &replaced},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diags := runInstanceModifyPlan(t, &prior, tc.planned)
+			if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Instance Deletion Protected" {
+				t.Errorf("Expected a deletion protection error, got %v", diags)
+			}
+		})
+	}
+}
+
+func TestModifyPlanDestroyUnprotected(t *testing.T) {
+	prior := testInstanceModel("web")
+	prior.DeletionProtection = types.BoolValue(false)
+
+	if diags := runInstanceModifyPlan(t, &prior, nil); len(diags) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diags)
+	}
+}
+
+func TestDeleteDeletionProtection(t *testing.T) {
+	ctx := context.Background()
+	r := &InstanceResource{client: NewMultipassClient("/non/existent/binary")}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	prior := testInstanceModel("web")
+	prior.DeletionProtection = types.BoolValue(true)
+
+	req := resource.DeleteRequest{State: tfsdk.State{Schema: schemaResp.Schema}}
+	if diags := req.State.Set(ctx, &prior); diags.HasError() {
+		t.Fatalf("Unable to build state: %v", diags)
+	}
+
+	resp := &resource.DeleteResponse{State: req.State}
+	r.Delete(ctx, req, resp)
+
+	if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Instance Deletion Protected" {
+		t.Errorf("Expected a deletion protection error before running multipass, got %v", resp.Diagnostics)
+	}
+}
@@ -2,17 +2,23 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sh05/terraform-provider-multipass/internal/common"
@@ -21,6 +27,9 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &InstanceResource{}
 var _ resource.ResourceWithImportState = &InstanceResource{}
+var _ resource.ResourceWithModifyPlan = &InstanceResource{}
+var _ resource.ResourceWithValidateConfig = &InstanceResource{}
+var _ resource.ResourceWithUpgradeState = &InstanceResource{}
 
 func NewInstanceResource() resource.Resource {
 	return &InstanceResource{}
@@ -33,16 +42,33 @@ type InstanceResource struct {
 
 // InstanceResourceModel describes the resource data model.
 type InstanceResourceModel struct {
-	Id               types.String   `tfsdk:"id"`
-	Name             types.String   `tfsdk:"name"`
-	Image            types.String   `tfsdk:"image"`
-	CPU              types.String   `tfsdk:"cpu"`
-	Memory           types.String   `tfsdk:"memory"`
-	Disk             types.String   `tfsdk:"disk"`
-	CloudInit        types.String   `tfsdk:"cloud_init"`
-	State            types.String   `tfsdk:"state"`
-	IPv4             types.List     `tfsdk:"ipv4"`
-	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+	Id                    types.String                `tfsdk:"id"`
+	Name                  types.String                `tfsdk:"name"`
+	Image                 types.String                `tfsdk:"image"`
+	ImageChecksum         types.String                `tfsdk:"image_checksum"`
+	ImageHash             types.String                `tfsdk:"image_hash"`
+	ImageRemote           types.String                `tfsdk:"image_remote"`
+	Blueprint             types.String                `tfsdk:"blueprint"`
+	Release               types.String                `tfsdk:"release"`
+	CPU                   types.String                `tfsdk:"cpu"`
+	Memory                types.String                `tfsdk:"memory"`
+	Disk                  types.String                `tfsdk:"disk"`
+	MemoryBytes           types.Int64                 `tfsdk:"memory_bytes"`
+	DiskBytes             types.Int64                 `tfsdk:"disk_bytes"`
+	CloudInit             types.String                `tfsdk:"cloud_init"`
+	CloudConfig           *CloudConfigModel           `tfsdk:"cloud_config"`
+	State                 types.String                `tfsdk:"state"`
+	IPv4                  types.List                  `tfsdk:"ipv4"`
+	OnCreateFailure       types.String                `tfsdk:"on_create_failure"`
+	DeletionProtection    types.Bool                  `tfsdk:"deletion_protection"`
+	StopTimeout           types.String                `tfsdk:"stop_timeout"`
+	ForceStopAfter        types.String                `tfsdk:"force_stop_after"`
+	RestartTriggers       types.Map                   `tfsdk:"restart_triggers"`
+	SnapshotBeforeDestroy *SnapshotBeforeDestroyModel `tfsdk:"snapshot_before_destroy"`
+	OnCreate              *HookModel                  `tfsdk:"on_create"`
+	BeforeStop            *HookModel                  `tfsdk:"before_stop"`
+	BeforeDestroy         *HookModel                  `tfsdk:"before_destroy"`
+	Timeouts              timeouts.Value              `tfsdk:"timeouts"`
 }
 
 func (r *InstanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -52,6 +78,7 @@ func (r *InstanceResource) Metadata(ctx context.Context, req resource.MetadataRe
 func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Multipass instance resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -62,18 +89,58 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Instance name",
-				Required:            true,
+				MarkdownDescription: "Instance name. Required unless the provider `defaults` block sets `name_prefix`, in which case a name is generated when the instance is created.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					instanceNameValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"image": schema.StringAttribute{
-				MarkdownDescription: "Ubuntu image to use (e.g. 'ubuntu', '22.04', '20.04')",
+				MarkdownDescription: "Ubuntu image to use (e.g. 'ubuntu', '22.04', '20.04'), or a custom image as an absolute `file://` path or an `https://` URL. Defaults to the provider `defaults` block, then to the multipass default.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					imageValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"image_checksum": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum (`<hex>` or `sha256:<hex>`) that a `file://` or `https://` image must match before the instance is launched. An `https://` image is downloaded once and launched from the verified copy.",
 				Optional:            true,
+				Validators: []validator.String{
+					imageChecksumValidator(),
+				},
+			},
+			"image_hash": schema.StringAttribute{
+				MarkdownDescription: "Hash of the image the instance was launched from, as reported by multipass. For a `file://` image it is the SHA-256 checksum of the file, computed when the instance is planned and again once the file's size or modification time changes, and the instance is replaced when the content changes.",
 				Computed:            true,
+			},
+			"image_remote": schema.StringAttribute{
+				MarkdownDescription: "Remote to take `image` from (e.g. 'daily' for `daily:24.04`, or 'snapcraft'). Defaults to the multipass release remote.",
+				Optional:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"blueprint": schema.StringAttribute{
+				MarkdownDescription: "Blueprint to launch instead of an image (e.g. 'docker', 'minikube'). Conflicts with `image` and `image_remote`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"release": schema.StringAttribute{
+				MarkdownDescription: "Release the instance runs, as reported by multipass after the launch (e.g. 'Ubuntu 24.04 LTS')",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
@@ -81,33 +148,63 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "Number of CPUs to allocate",
 				Optional:            true,
 				Computed:            true,
+				Validators: []validator.String{
+					cpuValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
 					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"memory": schema.StringAttribute{
 				MarkdownDescription: "Amount of memory to allocate (e.g. '1G', '512M')",
 				Optional:            true,
 				Computed:            true,
+				Validators: []validator.String{
+					memoryValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
 					stringplanmodifier.UseStateForUnknown(),
+					equivalentSizeModifier{},
+					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"disk": schema.StringAttribute{
 				MarkdownDescription: "Disk space to allocate (e.g. '5G', '10G')",
 				Optional:            true,
 				Computed:            true,
+				Validators: []validator.String{
+					diskValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
 					stringplanmodifier.UseStateForUnknown(),
+					equivalentSizeModifier{},
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"memory_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Memory allocation in bytes, null when multipass picks the default",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"disk_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Disk allocation in bytes, null when multipass picks the default",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 				},
 			},
 			"cloud_init": schema.StringAttribute{
 				MarkdownDescription: "Path to cloud-init configuration file",
 				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					cloudInitFileValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -120,6 +217,36 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				Computed:            true,
 				ElementType:         types.StringType,
 			},
+			"on_create_failure": schema.StringAttribute{
+				MarkdownDescription: "What to do with an instance that was launched but could not be fully created, e.g. after a launch timeout: `taint` saves it to state as tainted so the next apply replaces it, `delete` deletes it and `keep` leaves it unmanaged. Defaults to `taint`.",
+				Optional:            true,
+				Validators: []validator.String{
+					oneOfValidator("create failure mode", createFailureModes...),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to destroy or replace the instance while this is `true` in state. Set it to `false` and apply before changing attributes that force replacement.",
+				Optional:            true,
+			},
+			"stop_timeout": schema.StringAttribute{
+				MarkdownDescription: "Time allowed for stopping the instance, including a forced stop, before giving up (e.g. '2m'). Setting it also makes the provider stop a running instance before deleting it. Defaults to the operation timeout.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringValueValidator{label: "stop timeout", validate: validateTimeoutValue},
+				},
+			},
+			"force_stop_after": schema.StringAttribute{
+				MarkdownDescription: "Grace period for a clean shutdown, after which the instance is forced off with `multipass stop --force` (multipass 1.13+, e.g. '1m'). Setting it also makes the provider stop a running instance before deleting it.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringValueValidator{label: "force stop grace period", validate: validateTimeoutValue},
+				},
+			},
+			"restart_triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values, such as hashes of files pushed into the instance, whose change restarts a running instance in place instead of replacing it",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				Read:   true,
@@ -127,6 +254,101 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				Delete: true,
 			}),
 		},
+
+		Blocks: map[string]schema.Block{
+			"cloud_config":            cloudConfigBlock(),
+			"snapshot_before_destroy": snapshotBeforeDestroyBlock(),
+			"on_create":               hookBlock("Commands run once the instance has been launched. A failure is handled according to `on_create_failure`."),
+			"before_stop":             hookBlock("Commands run before the provider itself stops or restarts a running instance. This happens only while destroying or replacing an instance with `snapshot_before_destroy`, `stop_timeout` or `force_stop_after` set, and when `restart_triggers` changes; stops outside Terraform do not run it."),
+			"before_destroy":          hookBlock("Commands run in a running instance before it is destroyed or replaced, e.g. to drain it."),
+		},
+	}
+}
+
+func (r *InstanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data InstanceResourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.SnapshotBeforeDestroy != nil {
+		retain := data.SnapshotBeforeDestroy.Retain
+		if !retain.IsNull() && !retain.IsUnknown() && retain.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(path.Root("snapshot_before_destroy").AtName("retain"), "Invalid Attribute Value",
+				fmt.Sprintf("invalid retain %d: at least one backup must be kept", retain.ValueInt64()))
+		}
+
+		// Catch clone names multipass would reject before a destroy depends on them
+		name := data.Name
+		if data.SnapshotBeforeDestroy.backupMethod() == backupMethodClone && !name.IsNull() && !name.IsUnknown() &&
+			validateInstanceName(name.ValueString()) == nil {
+			clone := backupClonePrefix(name.ValueString()) + time.Now().UTC().Format(backupTimestampFormat)
+			if err := validateInstanceName(clone); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("snapshot_before_destroy").AtName("method"), "Invalid Attribute Value",
+					fmt.Sprintf("invalid backup clone name %q: %s", clone, err))
+			}
+		}
+	}
+
+	// Only custom images can be verified
+	image := data.Image
+	if !data.ImageChecksum.IsNull() && !image.IsUnknown() && !isImageURL(image.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("image_checksum"), "Invalid Attribute Combination",
+			"image_checksum requires image to be a file:// or https:// URL.")
+	}
+
+	// Blueprints bring their own image
+	if !data.Blueprint.IsNull() {
+		conflicts := []struct {
+			attribute string
+			value     types.String
+		}{
+			{"image", data.Image},
+			{"image_remote", data.ImageRemote},
+			{"image_checksum", data.ImageChecksum},
+		}
+		for _, conflict := range conflicts {
+			if !conflict.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(conflict.attribute), "Invalid Attribute Combination",
+					fmt.Sprintf("%s cannot be combined with blueprint.", conflict.attribute))
+			}
+		}
+	}
+
+	// Without image, the provider defaults may still supply one, so that
+	// case is left to ModifyPlan
+	if !image.IsNull() {
+		resp.Diagnostics.Append(checkImageRemote(data)...)
+	}
+
+	// A grace period outlasting the stop timeout would never force the instance off
+	timeout, hasTimeout := optionalDuration(data.StopTimeout)
+	grace, hasGrace := optionalDuration(data.ForceStopAfter)
+	if hasTimeout && hasGrace && grace >= timeout {
+		resp.Diagnostics.AddAttributeError(path.Root("force_stop_after"), "Invalid Attribute Value",
+			fmt.Sprintf("invalid force_stop_after %q: must be shorter than stop_timeout %q", data.ForceStopAfter.ValueString(), data.StopTimeout.ValueString()))
+	}
+
+	if data.Timeouts.IsNull() || data.Timeouts.IsUnknown() {
+		return
+	}
+
+	// The timeouts block only checks duration syntax, so enforce the same
+	// limits as the launch timeout
+	for operation, value := range data.Timeouts.Attributes() {
+		timeout, ok := value.(types.String)
+		if !ok || timeout.IsNull() || timeout.IsUnknown() {
+			continue
+		}
+
+		if err := validateTimeoutValue(timeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("timeouts").AtName(operation), "Invalid Attribute Value",
+				fmt.Sprintf("invalid %s timeout %q: %s", operation, timeout.ValueString(), err))
+		}
 	}
 }
 
@@ -171,43 +393,125 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	ctx, cancel := context.WithTimeout(ctx, createTimeout)
 	defer cancel()
 
+	var config InstanceResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fill in anything the plan could not settle, e.g. when the provider
+	// was not configured yet during planning
+	r.applyDefaults(&data, config)
+	if data.Name.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Missing Instance Name",
+			"Set name on the instance or name_prefix in the provider defaults block.")
+		return
+	}
+	if data.Name.IsUnknown() {
+		data.Name = types.StringValue(generateInstanceName(r.client.InstanceDefaults().NamePrefix))
+	}
+
+	// An https:// image is verified as a downloaded copy, which is then
+	// launched instead of the URL so that multipass gets the checked bytes
+	image := data.Image.ValueString()
+	if !data.ImageChecksum.IsNull() {
+		var err error
+		if strings.HasPrefix(image, imageHTTPSPrefix) {
+			var cleanup func()
+			image, cleanup, err = downloadImage(ctx, image, data.ImageChecksum.ValueString())
+			if cleanup != nil {
+				defer cleanup()
+			}
+		} else {
+			err = verifyImageChecksum(ctx, image, data.ImageChecksum.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("image_checksum"), "Image Verification Failed",
+				fmt.Sprintf("Unable to verify image %q, got error: %s", data.Image.ValueString(), err))
+			return
+		}
+	}
+
+	if data.ImageHash.IsUnknown() {
+		hash, err := localImageHash(ctx, data.Image)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("image"), "Unable to Hash Image",
+				fmt.Sprintf("Unable to read image %q, got error: %s", data.Image.ValueString(), err))
+			return
+		}
+		data.ImageHash = hash
+	}
+
+	// The cloud_config block already contains the cloud-init file
+	cloudInit, cloudInitData := data.CloudInit.ValueString(), ""
+	if data.CloudConfig != nil {
+		document, err := renderCloudConfig(data.CloudConfig, cloudInit)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("cloud_config"), "Unable to Render Cloud-Init",
+				fmt.Sprintf("Unable to render the cloud_config block, got error: %s", err))
+			return
+		}
+		cloudInit, cloudInitData = "", document
+	}
+
 	// Create launch options with Terraform create timeout
 	opts := &common.LaunchOptions{
-		Name:      data.Name.ValueString(),
-		Image:     data.Image.ValueString(),
-		CPU:       data.CPU.ValueString(),
-		Memory:    data.Memory.ValueString(),
-		Disk:      data.Disk.ValueString(),
-		CloudInit: data.CloudInit.ValueString(),
-		Timeout:   createTimeout.String(),
+		Name:          data.Name.ValueString(),
+		Image:         image,
+		Remote:        data.ImageRemote.ValueString(),
+		Blueprint:     data.Blueprint.ValueString(),
+		CPU:           data.CPU.ValueString(),
+		Memory:        data.Memory.ValueString(),
+		Disk:          data.Disk.ValueString(),
+		CloudInit:     cloudInit,
+		CloudInitData: cloudInitData,
+		Timeout:       createTimeout.String(),
 	}
 
 	// Launch the instance
 	tflog.Trace(ctx, "launching multipass instance", map[string]interface{}{"name": opts.Name})
 
-	err := r.client.Launch(opts)
-	if err != nil {
+	err := r.client.Launch(ctx, opts)
+	if errors.Is(err, ErrInstanceExists) {
+		// The instance belongs to someone else, so it must not be touched
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create instance, got error: %s", err))
 		return
 	}
+	if err != nil {
+		r.recoverFailedCreate(ctx, &data, resp, fmt.Errorf("unable to create instance: %w", err))
+		return
+	}
 
 	// Set the ID
 	data.Id = data.Name
 
 	// Read the instance to get current state
-	instance, err := r.client.GetInstance(data.Name.ValueString())
+	instance, err := r.client.GetInstance(ctx, data.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read instance after creation, got error: %s", err))
+		r.recoverFailedCreate(ctx, &data, resp, fmt.Errorf("unable to read instance after creation: %w", err))
 		return
 	}
 
 	// Update the model with instance data
 	r.updateModelFromInstance(&data, instance)
 
+	if err := r.runHook(ctx, data.Name.ValueString(), hookOnCreate, data.OnCreate); err != nil {
+		if data.OnCreate.failOnError() {
+			r.recoverFailedCreate(ctx, &data, resp, err)
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(path.Root(hookOnCreate), "Instance Hook Failed",
+			fmt.Sprintf("Instance %q: %s. Continuing since fail_on_error is false.", data.Name.ValueString(), err))
+	}
+
 	tflog.Trace(ctx, "created multipass instance")
 
 	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, imageFilePrivateKey, imageFileStampData(data.Image))...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setInstanceIdentity(ctx, resp.Identity, data.Name.ValueString())...)
 }
 
 func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -227,14 +531,15 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(ctx, readTimeout)
-	defer cancel()
-
-	// Get the instance
-	instance, err := r.client.GetInstance(data.Name.ValueString())
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+	// Get the instance, waiting up to the read timeout for it to finish
+	// starting or stopping
+	instance, err := r.client.WaitForStableStateWithin(ctx, data.Name.ValueString(), readTimeout)
+	if errors.Is(err, ErrStateWaitTimeout) {
+		// Record the transitional state rather than failing every plan
+		resp.Diagnostics.AddWarning("Instance State Not Stable",
+			fmt.Sprintf("Unable to wait for a stable instance state within the read timeout: %s", err))
+	} else if err != nil {
+		if errors.Is(err, ErrInstanceNotFound) {
 			// Instance doesn't exist, remove from state
 			resp.State.RemoveResource(ctx)
 			return
@@ -248,13 +553,15 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setInstanceIdentity(ctx, resp.Identity, data.Name.ValueString())...)
 }
 
 func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data InstanceResourceModel
+	var data, state InstanceResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -275,8 +582,36 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 	// changing instance configuration after creation. The schema marks most
 	// attributes as requiring replacement.
 
+	// Record the hash of an image launched before image_hash existed
+	if data.ImageHash.IsUnknown() {
+		hash, err := localImageHash(ctx, data.Image)
+		if err != nil {
+			resp.Diagnostics.AddAttributeWarning(path.Root("image"), "Unable to Hash Image",
+				fmt.Sprintf("Unable to read image %q, got error: %s", data.Image.ValueString(), err))
+		}
+		data.ImageHash = hash
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, imageFilePrivateKey, imageFileStampData(data.Image))...)
+
+	if restartTriggersChanged(state.RestartTriggers, data.RestartTriggers) {
+		resp.Diagnostics.Append(r.restartInstance(ctx, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Refresh the computed attributes, which are unknown in the plan
+	instance, err := r.client.WaitForStableState(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read instance, got error: %s", err))
+		return
+	}
+
+	r.updateModelFromInstance(&data, instance)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setInstanceIdentity(ctx, resp.Identity, data.Name.ValueString())...)
 }
 
 func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -300,10 +635,68 @@ func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteReques
 	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
 	defer cancel()
 
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Instance Deletion Protected", deletionProtectedDetail(data.Name.ValueString()))
+		return
+	}
+
+	// Let a starting or stopping instance settle briefly, since multipass
+	// may refuse to delete it mid-transition
+	instance, err := r.client.WaitBeforeDelete(ctx, data.Name.ValueString())
+	if errors.Is(err, ErrInstanceNotFound) {
+		tflog.Trace(ctx, "multipass instance already deleted")
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete instance, got error: %s", err))
+		return
+	}
+
+	// Commands such as draining need a running instance
+	if instance.State == common.StateRunning {
+		resp.Diagnostics.Append(r.runHookDiagnostics(ctx, data.Name.ValueString(), hookBeforeDestroy, data.BeforeDestroy)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else if data.BeforeDestroy != nil {
+		tflog.Warn(ctx, "skipping before_destroy hook of an instance that is not running", map[string]interface{}{
+			"name":  data.Name.ValueString(),
+			"state": instance.State.String(),
+		})
+	}
+
+	backup := data.SnapshotBeforeDestroy != nil && instance.State != common.StateDeleted
+	if backup {
+		resp.Diagnostics.Append(r.checkBackupCapability(data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Backups need a stopped instance, and stop options bound a shutdown
+	// that multipass delete would otherwise wait on indefinitely
+	if backup || (instance.State == common.StateRunning && data.stopConfigured()) {
+		resp.Diagnostics.Append(r.stopInstance(ctx, &data, instance.State)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Keep a copy of the instance when configured. A snapshot only survives
+	// as long as the instance is not purged.
+	purge := true
+	if backup {
+		purge, err = r.backupBeforeDelete(ctx, data.Name.ValueString(), data.SnapshotBeforeDestroy)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to back up instance before deleting it, got error: %s", err))
+			return
+		}
+	}
+
 	// Delete the instance
 	tflog.Trace(ctx, "deleting multipass instance", map[string]interface{}{"name": data.Name.ValueString()})
 
-	err := r.client.DeleteInstance(data.Name.ValueString(), true)
+	err = r.client.DeleteInstance(ctx, data.Name.ValueString(), purge)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete instance, got error: %s", err))
 		return
@@ -312,13 +705,163 @@ func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteReques
 	tflog.Trace(ctx, "deleted multipass instance")
 }
 
+func (r *InstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Protected instances must survive destroy and replacement plans
+	if req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(checkDestroyProtection(ctx, req.State)...)
+		return
+	}
+
+	// Defaults only apply to new instances
+	if !req.State.Raw.IsNull() {
+		var plan, state InstanceResourceModel
+
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		stamp, diags := req.Private.GetKey(ctx, imageFilePrivateKey)
+		resp.Diagnostics.Append(diags...)
+
+		planImageHash(ctx, &plan, &state, parseImageFileStamp(stamp), resp)
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		resp.Diagnostics.Append(r.checkBackupCapability(plan)...)
+		resp.Diagnostics.Append(r.checkStopCapability(plan)...)
+		resp.Diagnostics.Append(checkReplacement(ctx, req.State, resp.Plan)...)
+		return
+	}
+
+	// The provider may not be configured yet, Create applies defaults then
+	if r.client == nil {
+		return
+	}
+
+	var plan, config InstanceResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Show provider defaults in the plan instead of "known after apply"
+	r.applyDefaults(&plan, config)
+
+	if plan.Name.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Missing Instance Name",
+			"Set name on the instance or name_prefix in the provider defaults block.")
+		return
+	}
+
+	resp.Diagnostics.Append(checkImageRemote(plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planImageHash(ctx, &plan, nil, nil, resp)
+	resp.Diagnostics.Append(r.checkImageAvailable(ctx, plan)...)
+	resp.Diagnostics.Append(r.checkHostCapacity(ctx, plan)...)
+	resp.Diagnostics.Append(r.checkBackupCapability(plan)...)
+	resp.Diagnostics.Append(r.checkStopCapability(plan)...)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// checkHostCapacity adds the planned instance to the allocations of this plan
+// and reports when their total exceeds what the host can provide
+func (r *InstanceResource) checkHostCapacity(ctx context.Context, plan InstanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	config := r.client.CapacityCheck()
+	if config.Mode == capacityCheckOff {
+		return diags
+	}
+
+	// Instances named at apply time are counted under an empty name
+	exceeded, err := r.client.PlanCapacity(plan.Name.ValueString(), plannedAllocation(plan))
+	if err != nil {
+		tflog.Warn(ctx, "skipping host capacity check", map[string]interface{}{
+			"error": err.Error(),
+		})
+		diags.AddWarning("Unable to Check Host Capacity",
+			fmt.Sprintf("The planned instances could not be compared with the host resources: %s", err))
+		return diags
+	}
+
+	for _, usage := range exceeded {
+		summary := "Host Capacity Exceeded"
+		detail := fmt.Sprintf("Instance %s: %s. Adjust the capacity_check block of the provider to change this check.",
+			plannedName(plan), usage)
+		if config.Mode == capacityCheckError {
+			diags.AddAttributeError(path.Root(usage.Resource), summary, detail)
+		} else {
+			diags.AddAttributeWarning(path.Root(usage.Resource), summary, detail)
+		}
+	}
+
+	return diags
+}
+
+// plannedName quotes the planned instance name for diagnostics
+func plannedName(plan InstanceResourceModel) string {
+	if plan.Name.IsUnknown() {
+		return "(known after apply)"
+	}
+	return strconv.Quote(plan.Name.ValueString())
+}
+
+// plannedAllocation returns the resources an instance will be launched with,
+// using the multipass defaults for omitted values and -1 for unknown ones
+func plannedAllocation(plan InstanceResourceModel) instanceAllocation {
+	allocation := instanceAllocation{
+		CPUs:   multipassDefaultCPUs,
+		Memory: multipassDefaultMemory,
+		Disk:   multipassDefaultDisk,
+	}
+
+	if plan.CPU.IsUnknown() {
+		allocation.CPUs = -1
+	} else if !plan.CPU.IsNull() {
+		cpus, err := strconv.ParseInt(plan.CPU.ValueString(), 10, 64)
+		if err != nil {
+			cpus = -1
+		}
+		allocation.CPUs = cpus
+	}
+
+	allocation.Memory = plannedSize(plan.Memory, allocation.Memory)
+	allocation.Disk = plannedSize(plan.Disk, allocation.Disk)
+
+	return allocation
+}
+
+// plannedSize converts a planned size attribute to bytes
+func plannedSize(value types.String, def int64) int64 {
+	if value.IsUnknown() {
+		return -1
+	}
+	if value.IsNull() {
+		return def
+	}
+	size, err := parseSize(value.ValueString())
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
 func (r *InstanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	// Import by name, either as the import ID or the identity name
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("name"), path.Root("name"), req, resp)
 }
 
 // updateModelFromInstance updates the resource model with data from a Multipass instance
 func (r *InstanceResource) updateModelFromInstance(data *InstanceResourceModel, instance *common.MultipassInstance) {
-	data.State = types.StringValue(instance.State)
+	data.State = types.StringValue(instance.State.String())
 
 	// Convert IPv4 addresses to list
 	if len(instance.IPv4) > 0 {
@@ -327,5 +870,70 @@ func (r *InstanceResource) updateModelFromInstance(data *InstanceResourceModel,
 			ipv4Values[i] = types.StringValue(ip)
 		}
 		data.IPv4 = types.ListValueMust(types.StringType, ipv4Values)
+	} else {
+		data.IPv4 = types.ListValueMust(types.StringType, []attr.Value{})
+	}
+
+	recordImageDetails(data, instance)
+}
+
+// applyDefaults fills launch settings omitted from the configuration with
+// the provider defaults, or with null when multipass picks the value itself
+func (r *InstanceResource) applyDefaults(data *InstanceResourceModel, config InstanceResourceModel) {
+	var defaults common.InstanceDefaults
+	if r.client != nil {
+		defaults = r.client.InstanceDefaults()
+	}
+
+	defaultImage := defaults.Image
+	if !config.Blueprint.IsNull() {
+		defaultImage = ""
+	}
+
+	data.Image = stringWithDefault(data.Image, config.Image, defaultImage)
+	data.CPU = stringWithDefault(data.CPU, config.CPU, defaults.CPU)
+	data.Memory = stringWithDefault(data.Memory, config.Memory, defaults.Memory)
+	data.Disk = stringWithDefault(data.Disk, config.Disk, defaults.Disk)
+	data.CloudInit = stringWithDefault(data.CloudInit, config.CloudInit, defaults.CloudInit)
+	data.MemoryBytes = sizeBytes(data.Memory)
+	data.DiskBytes = sizeBytes(data.Disk)
+
+	// With name_prefix the name stays unknown until Create generates it,
+	// since Terraform plans again during apply and both plans must agree
+	if config.Name.IsNull() && data.Name.IsUnknown() && defaults.NamePrefix == "" {
+		data.Name = types.StringNull()
+	}
+}
+
+// stringWithDefault returns the planned value, replacing it with the default
+// (or null without one) when the attribute is omitted from the configuration
+func stringWithDefault(planned types.String, configured types.String, def string) types.String {
+	if !configured.IsNull() || !planned.IsUnknown() {
+		return planned
 	}
+	if def == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(def)
+}
+
+// sizeBytes converts a size attribute to bytes, keeping null and unknown
+// values and returning null for sizes that cannot be parsed
+func sizeBytes(value types.String) types.Int64 {
+	if value.IsUnknown() {
+		return types.Int64Unknown()
+	}
+	if value.IsNull() {
+		return types.Int64Null()
+	}
+	size, err := parseSize(value.ValueString())
+	if err != nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(size)
+}
+
+// generateInstanceName returns prefix followed by 8 random hexadecimal characters
+func generateInstanceName(prefix string) string {
+	return fmt.Sprintf("%s%08x", prefix, rand.Uint32())
 }
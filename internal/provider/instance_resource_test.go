@@ -1,12 +1,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
 )
 
 func TestAccInstanceResource(t *testing.T) {
@@ -312,7 +316,7 @@ func TestAccInstanceResource_ResourceLimits(t *testing.T) {
 		Steps: []resource.TestStep{
 			{
 				Config:      testAccInstanceResourceConfigExcessiveResources(),
-				ExpectError: regexp.MustCompile(`resource allocation failed|insufficient resources|failed to launch instance`),
+				ExpectError: regexp.MustCompile(`exceeds maximum|resource allocation failed|insufficient resources|failed to launch instance`),
 			},
 		},
 	})
@@ -347,3 +351,114 @@ func testCheckInstanceDoesNotExist(resourceName string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+// TestAccInstanceResource_ProviderDefaults tests that provider defaults fill omitted attributes
+func TestAccInstanceResource_ProviderDefaults(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceResourceConfigProviderDefaults(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("multipass_instance.test", "name", regexp.MustCompile(`^tf-defaults-[0-9a-f]{8}$`)),
+					resource.TestCheckResourceAttr("multipass_instance.test", "image", "22.04"),
+					resource.TestCheckResourceAttr("multipass_instance.test", "cpu", "1"),
+					resource.TestCheckResourceAttr("multipass_instance.test", "memory", "2G"),
+					resource.TestCheckResourceAttr("multipass_instance.test", "disk", "5G"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceResourceConfigProviderDefaults() string {
+	return `
+provider "multipass" {
+  defaults {
+    image       = "22.04"
+    cpu         = "1"
+    memory      = "1G"
+    disk        = "5G"
+    name_prefix = "tf-defaults-"
+  }
+}
+
+resource "multipass_instance" "test" {
+  memory = "2G"
+}
+`
+}
+
+// TestStringWithDefault tests merging of provider defaults into planned values
+func TestStringWithDefault(t *testing.T) {
+	testCases := []struct {
+		name       string
+		planned    types.String
+		configured types.String
+		def        string
+		want       types.String
+	}{
+		{"Configured value wins", types.StringValue("4G"), types.StringValue("4G"), "2G", types.StringValue("4G")},
+		{"Omitted uses default", types.StringUnknown(), types.StringNull(), "2G", types.StringValue("2G")},
+		{"Omitted without default is null", types.StringUnknown(), types.StringNull(), "", types.StringNull()},
+		{"Unknown configuration stays unknown", types.StringUnknown(), types.StringUnknown(), "2G", types.StringUnknown()},
+		{"Prior state is kept", types.StringValue("1G"), types.StringNull(), "2G", types.StringValue("1G")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := stringWithDefault(tc.planned, tc.configured, tc.def); !got.Equal(tc.want) {
+				t.Errorf("stringWithDefault() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestGenerateInstanceName tests names generated from name_prefix
+func TestGenerateInstanceName(t *testing.T) {
+	name := generateInstanceName("team-")
+	if !regexp.MustCompile(`^team-[0-9a-f]{8}$`).MatchString(name) {
+		t.Errorf("Unexpected generated name: %s", name)
+	}
+	if name == generateInstanceName("team-") {
+		t.Error("Expected generated names to differ")
+	}
+}
+
+// TestModifyPlanNamePrefix tests that name_prefix leaves the name to Create,
+// so the plan Terraform computes again during apply stays the same
+func TestModifyPlanNamePrefix(t *testing.T) {
+	client := NewMultipassClient("/nonexistent/multipass")
+	client.SetInstanceDefaults(common.InstanceDefaults{NamePrefix: "team-"})
+	client.SetCapacityCheck(CapacityCheckConfig{Mode: capacityCheckOff})
+	r := &InstanceResource{client: client}
+
+	config := testInstanceModel("")
+	config.Id = types.StringNull()
+	config.Name = types.StringNull()
+	config.State = types.StringNull()
+	config.IPv4 = types.ListNull(types.StringType)
+
+	planned := config
+	planned.Id = types.StringUnknown()
+	planned.Name = types.StringUnknown()
+	planned.State = types.StringUnknown()
+	planned.IPv4 = types.ListUnknown(types.StringType)
+
+	var names []types.String
+	for range 2 {
+		resp := modifyInstancePlanWithConfig(t, r, nil, &planned, &config)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected errors: %v", resp.Diagnostics)
+		}
+
+		var name types.String
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("name"), &name)...)
+		names = append(names, name)
+	}
+
+	if !names[0].IsUnknown() || !names[0].Equal(names[1]) {
+		t.Errorf("Expected the name to stay unknown in every plan, got %s and %s", names[0], names[1])
+	}
+}
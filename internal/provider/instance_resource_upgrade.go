@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// InstanceResourceModelV0 describes the resource data model of schema version 0.
+type InstanceResourceModelV0 struct {
+	Id        types.String   `tfsdk:"id"`
+	Name      types.String   `tfsdk:"name"`
+	Image     types.String   `tfsdk:"image"`
+	CPU       types.String   `tfsdk:"cpu"`
+	Memory    types.String   `tfsdk:"memory"`
+	Disk      types.String   `tfsdk:"disk"`
+	CloudInit types.String   `tfsdk:"cloud_init"`
+	State     types.String   `tfsdk:"state"`
+	IPv4      types.List     `tfsdk:"ipv4"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *InstanceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := instanceResourceSchemaV0(ctx)
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeInstanceStateV0,
+		},
+	}
+}
+
+// instanceResourceSchemaV0 returns the schema of version 0, which had no
+// memory_bytes and disk_bytes attributes
+func instanceResourceSchemaV0(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"image": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"cpu": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"memory": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"disk": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"cloud_init": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"state": schema.StringAttribute{
+				Computed: true,
+			},
+			"ipv4": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// upgradeInstanceStateV0 converts a version 0 state. Empty launch settings
+// written by early releases become null, memory and disk sizes are
+// normalized (e.g. "1024M" becomes "1G") and also recorded in bytes, and a
+// missing ipv4 list becomes an empty one as returned by reads since.
+func upgradeInstanceStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior InstanceResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := InstanceResourceModel{
+		Id:        prior.Id,
+		Name:      prior.Name,
+		Image:     nullIfEmpty(prior.Image),
+		CPU:       nullIfEmpty(prior.CPU),
+		Memory:    normalizeSize(nullIfEmpty(prior.Memory)),
+		Disk:      normalizeSize(nullIfEmpty(prior.Disk)),
+		CloudInit: nullIfEmpty(prior.CloudInit),
+		State:     prior.State,
+		IPv4:      prior.IPv4,
+		Timeouts:  prior.Timeouts,
+	}
+
+	// Attributes added after version 0 carry their element types
+	upgraded.RestartTriggers = types.MapNull(types.StringType)
+
+	upgraded.MemoryBytes = sizeBytes(upgraded.Memory)
+	upgraded.DiskBytes = sizeBytes(upgraded.Disk)
+
+	if upgraded.IPv4.IsNull() || upgraded.IPv4.IsUnknown() {
+		upgraded.IPv4 = types.ListValueMust(types.StringType, []attr.Value{})
+	}
+
+	tflog.Debug(ctx, "upgraded multipass instance state from version 0", map[string]interface{}{
+		"name": upgraded.Name.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &upgraded)...)
+}
+
+// nullIfEmpty returns null for empty or blank strings
+func nullIfEmpty(value types.String) types.String {
+	if value.IsNull() || value.IsUnknown() || strings.TrimSpace(value.ValueString()) != "" {
+		return value
+	}
+	return types.StringNull()
+}
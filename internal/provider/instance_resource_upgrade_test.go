@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// upgradeInstanceState feeds raw JSON state of the given version through the
+// provider server and decodes the result with the current schema
+func upgradeInstanceState(t *testing.T, version int64, rawState string) InstanceResourceModel {
+	t.Helper()
+	ctx := context.Background()
+
+	server := providerserver.NewProtocol6(New("test")())()
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "multipass_instance",
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: []byte(rawState)},
+	})
+	if err != nil {
+		t.Fatalf("UpgradeResourceState() error = %v", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("UpgradeResourceState() diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	var schemaResp resource.SchemaResponse
+	NewInstanceResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	value, err := resp.UpgradedState.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("Unable to decode upgraded state: %v", err)
+	}
+
+	var model InstanceResourceModel
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: value}
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("Unable to read upgraded state: %v", diags)
+	}
+	return model
+}
+
+func TestUpgradeInstanceStateV0(t *testing.T) {
+	model := upgradeInstanceState(t, 0, `{
+		"id": "web",
+		"name": "web",
+		"image": "22.04",
+		"cpu": "2",
+		"memory": "1.5GiB",
+		"disk": "10G",
+		"cloud_init": "./cloud-init.yaml",
+		"state": "Running",
+		"ipv4": ["10.0.0.5"],
+		"timeouts": {"create": "20m"}
+	}`)
+
+	if model.Name.ValueString() != "web" || model.Image.ValueString() != "22.04" || model.CPU.ValueString() != "2" {
+		t.Errorf("Expected launch settings to be kept, got %+v", model)
+	}
+	if model.Memory.ValueString() != "1536M" {
+		t.Errorf("Expected memory to be normalized to 1536M, got %s", model.Memory)
+	}
+	if model.MemoryBytes.ValueInt64() != 3<<29 {
+		t.Errorf("Expected memory_bytes %d, got %s", int64(3<<29), model.MemoryBytes)
+	}
+	if model.DiskBytes.ValueInt64() != 10<<30 {
+		t.Errorf("Expected disk_bytes %d, got %s", int64(10<<30), model.DiskBytes)
+	}
+	if model.CloudInit.ValueString() != "./cloud-init.yaml" {
+		t.Errorf("Expected cloud_init to be kept, got %s", model.CloudInit)
+	}
+	if len(model.IPv4.Elements()) != 1 {
+		t.Errorf("Expected one ipv4 address, got %s", model.IPv4)
+	}
+
+	timeout, diags := model.Timeouts.Create(context.Background(), 0)
+	if diags.HasError() || timeout.String() != "20m0s" {
+		t.Errorf("Expected create timeout 20m0s, got %s (%v)", timeout, diags)
+	}
+}
+
+func TestUpgradeInstanceStateV0EmptyValues(t *testing.T) {
+	model := upgradeInstanceState(t, 0, `{
+		"id": "web",
+		"name": "web",
+		"image": "",
+		"cpu": "",
+		"memory": "",
+		"disk": null,
+		"cloud_init": "",
+		"state": "Stopped",
+		"ipv4": null,
+		"timeouts": null
+	}`)
+
+	for attribute, value := range map[string]interface{ IsNull() bool }{
+		"image":        model.Image,
+		"cpu":          model.CPU,
+		"memory":       model.Memory,
+		"disk":         model.Disk,
+		"cloud_init":   model.CloudInit,
+		"memory_bytes": model.MemoryBytes,
+		"disk_bytes":   model.DiskBytes,
+	} {
+		if !value.IsNull() {
+			t.Errorf("Expected %s to be null, got %v", attribute, value)
+		}
+	}
+
+	if model.IPv4.IsNull() || len(model.IPv4.Elements()) != 0 {
+		t.Errorf("Expected an empty ipv4 list, got %s", model.IPv4)
+	}
+	if model.State.ValueString() != "Stopped" {
+		t.Errorf("Expected state Stopped, got %s", model.State)
+	}
+}
+
+func TestUpgradeInstanceStateV0Sizes(t *testing.T) {
+	testCases := []struct {
+		memory, disk         string
+		wantMemory, wantDisk string
+	}{
+		{memory: "1024M", disk: "10240M", wantMemory: "1G", wantDisk: "10G"},
+		{memory: "1G", disk: "5G", wantMemory: "1G", wantDisk: "5G"},
+		{memory: "2gib", disk: "1T", wantMemory: "2G", wantDisk: "1T"},
+		{memory: "512MB", disk: "1536M", wantMemory: "512M", wantDisk: "1536M"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.memory+"/"+tc.disk, func(t *testing.T) {
+			model := upgradeInstanceState(t, 0, fmt.Sprintf(`{
+				"id": "web",
+				"name": "web",
+				"memory": %q,
+				"disk": %q,
+				"state": "Running",
+				"ipv4": []
+			}`, tc.memory, tc.disk))
+
+			if model.Memory.ValueString() != tc.wantMemory || model.Disk.ValueString() != tc.wantDisk {
+				t.Errorf("Expected memory %s and disk %s, got %s and %s", tc.wantMemory, tc.wantDisk, model.Memory, model.Disk)
+			}
+		})
+	}
+}
+
+func TestUpgradeInstanceStateV0InvalidSize(t *testing.T) {
+	model := upgradeInstanceState(t, 0, `{
+		"id": "web",
+		"name": "web",
+		"memory": "lots",
+		"state": "Running",
+		"ipv4": []
+	}`)
+
+	if model.Memory.ValueString() != "lots" || !model.MemoryBytes.IsNull() {
+		t.Errorf("Expected unparseable memory to be kept with null memory_bytes, got %s and %s", model.Memory, model.MemoryBytes)
+	}
+}
+
+func TestSizeBytes(t *testing.T) {
+	if got := sizeBytes(types.StringValue("512M")); got.ValueInt64() != 512<<20 {
+		t.Errorf("sizeBytes(512M) = %s", got)
+	}
+	if got := sizeBytes(types.StringNull()); !got.IsNull() {
+		t.Errorf("sizeBytes(null) = %s, want null", got)
+	}
+	if got := sizeBytes(types.StringUnknown()); !got.IsUnknown() {
+		t.Errorf("sizeBytes(unknown) = %s, want unknown", got)
+	}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// restartTriggersChanged reports whether restart_triggers changed, treating
+// an omitted map and an empty one alike since neither holds a trigger
+func restartTriggersChanged(prior types.Map, planned types.Map) bool {
+	if len(prior.Elements()) == 0 && len(planned.Elements()) == 0 {
+		return false
+	}
+	return !prior.Equal(planned)
+}
+
+// restartInstance restarts a running instance in place after its
+// restart_triggers changed, running the before_stop hook first, and waits
+// until it is back up with an IPv4 address. Other instances pick up the
+// change when they are next started, so they are left alone.
+func (r *InstanceResource) restartInstance(ctx context.Context, data *InstanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	name := data.Name.ValueString()
+
+	instance, err := r.client.WaitForStableState(ctx, name)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read instance, got error: %s", err))
+		return diags
+	}
+
+	if instance.State != common.StateRunning {
+		tflog.Info(ctx, "skipping restart of multipass instance that is not running", map[string]interface{}{
+			"name":  name,
+			"state": instance.State.String(),
+		})
+		return diags
+	}
+
+	diags.Append(r.runHookDiagnostics(ctx, name, hookBeforeStop, data.BeforeStop)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Info(ctx, "restarting multipass instance after restart_triggers changed", map[string]interface{}{"name": name})
+
+	if err := r.client.RestartInstance(ctx, name); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to restart instance, got error: %s", err))
+		return diags
+	}
+
+	if _, err := r.client.WaitForRunning(ctx, name); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Instance did not come back up after the restart, got error: %s", err))
+	}
+
+	return diags
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeRestartBinary creates a fake multipass binary reporting instance
+// "web" in the given state, then Starting without an address for the first
+// info call after a restart
+func writeRestartBinary(t *testing.T, state string) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, fmt.Sprintf(`if [ "$1" = "info" ]; then
+  count=$(grep -c '^info' "$calls")
+  if [ "$count" -eq 2 ]; then
+    echo '{"errors": [], "info": {"web": {"state": "Starting", "ipv4": []}}}'
+  else
+    echo '{"errors": [], "info": {"web": {"state": "%s", "ipv4": ["10.0.0.5"]}}}'
+  fi
+fi
+exit 0
+`, state))
+}
+
+func TestRestartInstance(t *testing.T) {
+	binary, calls := writeRestartBinary(t, "Running")
+	r := &InstanceResource{client: newStateWaitClient(binary)}
+
+	data := testInstanceModel("web")
+	data.BeforeStop = testHook("systemctl stop app")
+
+	if diags := r.restartInstance(context.Background(), &data); diags.HasError() {
+		t.Fatalf("restartInstance() diagnostics = %v", diags)
+	}
+
+	var commands []string
+	for _, call := range readCalls(t, calls) {
+		commands = append(commands, strings.Fields(call)[0])
+	}
+
+	// The restart waits until the instance is Running with an address again
+	if got, want := strings.Join(commands, ","), "info,exec,restart,info,info"; got != want {
+		t.Errorf("Unexpected commands %s, want %s", got, want)
+	}
+}
+
+func TestRestartInstanceNotRunning(t *testing.T) {
+	binary, calls := writeRestartBinary(t, "Stopped")
+	r := &InstanceResource{client: newStateWaitClient(binary)}
+
+	data := testInstanceModel("web")
+	if diags := r.restartInstance(context.Background(), &data); diags.HasError() {
+		t.Fatalf("restartInstance() diagnostics = %v", diags)
+	}
+
+	for _, call := range readCalls(t, calls) {
+		if strings.HasPrefix(call, "restart") {
+			t.Errorf("Expected a stopped instance not to be restarted, got %q", call)
+		}
+	}
+}
+
+func TestRestartTriggersChanged(t *testing.T) {
+	triggers := func(value string) types.Map {
+		return types.MapValueMust(types.StringType, map[string]attr.Value{"config": types.StringValue(value)})
+	}
+	empty := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	null := types.MapNull(types.StringType)
+
+	testCases := []struct {
+		name    string
+		prior   types.Map
+		planned types.Map
+		want    bool
+	}{
+		{"Added empty", null, empty, false},
+		{"Removed empty", empty, null, false},
+		{"Unchanged", triggers("a"), triggers("a"), false},
+		{"Changed", triggers("a"), triggers("b"), true},
+		{"Added", null, triggers("a"), true},
+		{"Removed", triggers("a"), null, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := restartTriggersChanged(tc.prior, tc.planned); got != tc.want {
+				t.Errorf("restartTriggersChanged() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWaitForRunningTimeout(t *testing.T) {
+	binary, _ := writeStateBinary(t, common.StateStarting)
+	client := newStateWaitClient(binary)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForRunning(ctx, "web")
+	if !errors.Is(err, ErrRunningWaitTimeout) {
+		t.Fatalf("Expected ErrRunningWaitTimeout, got %v", err)
+	}
+}
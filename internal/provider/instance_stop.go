@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// optionalDuration returns a configured duration, reporting false when it is
+// not set
+func optionalDuration(value types.String) (time.Duration, bool) {
+	if value.IsNull() || value.IsUnknown() {
+		return 0, false
+	}
+
+	duration, err := time.ParseDuration(value.ValueString())
+	if err != nil || duration <= 0 {
+		return 0, false
+	}
+
+	return duration, true
+}
+
+// stopConfigured reports whether the instance sets stop options, so that it
+// is stopped by the provider rather than by multipass delete
+func (m *InstanceResourceModel) stopConfigured() bool {
+	_, timeout := optionalDuration(m.StopTimeout)
+	_, force := optionalDuration(m.ForceStopAfter)
+	return timeout || force
+}
+
+// checkStopCapability reports when the installed multipass cannot force
+// instances off as force_stop_after requires
+func (r *InstanceResource) checkStopCapability(plan InstanceResourceModel) diag.Diagnostics {
+	if plan.ForceStopAfter.IsNull() || r.client == nil {
+		return nil
+	}
+
+	return r.client.requireCapability(CapabilityForceStop, path.Root("force_stop_after"))
+}
+
+// stopInstance runs the before_stop hook of a running instance and stops it
+func (r *InstanceResource) stopInstance(ctx context.Context, data *InstanceResourceModel, state common.InstanceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	name := data.Name.ValueString()
+
+	if state == common.StateRunning {
+		diags.Append(r.runHookDiagnostics(ctx, name, hookBeforeStop, data.BeforeStop)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if err := r.gracefulStop(ctx, data); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to stop instance, got error: %s", err))
+	}
+
+	return diags
+}
+
+// gracefulStop stops an instance within stop_timeout, forcing it off when a
+// clean shutdown takes longer than force_stop_after
+func (r *InstanceResource) gracefulStop(ctx context.Context, data *InstanceResourceModel) error {
+	name := data.Name.ValueString()
+
+	if timeout, ok := optionalDuration(data.StopTimeout); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	grace, force := optionalDuration(data.ForceStopAfter)
+	if !force {
+		err := r.client.StopInstance(ctx, name)
+		if err != nil && ctx.Err() != nil {
+			return fmt.Errorf("instance did not stop within stop_timeout %s: %w", data.StopTimeout.ValueString(), err)
+		}
+		return err
+	}
+
+	graceCtx, cancel := context.WithTimeout(ctx, grace)
+	err := r.client.StopInstance(graceCtx, name)
+	cancel()
+
+	// Only an expired grace period is worth forcing, other failures and an
+	// expired stop_timeout are reported as they are
+	if err == nil || graceCtx.Err() == nil || ctx.Err() != nil {
+		return err
+	}
+
+	tflog.Warn(ctx, "forcing multipass instance off after the stop grace period", map[string]interface{}{
+		"name":             name,
+		"force_stop_after": grace.String(),
+	})
+
+	if err := r.client.StopInstanceWithOptions(ctx, name, StopOptions{Force: true}); err != nil {
+		return fmt.Errorf("instance did not stop within force_stop_after %s and forcing it off failed: %w", grace, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// writeStopBinary creates a fake multipass binary whose clean stop hangs
+// while a forced stop succeeds
+func writeStopBinary(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, `if [ "$*" = "stop web" ]; then
+  exec sleep 10
+fi
+exit 0
+`)
+}
+
+func TestStopInstanceWithOptions(t *testing.T) {
+	binary, calls := writeStopBinary(t)
+	client := NewMultipassClient(binary)
+	ctx := context.Background()
+
+	if err := client.StopInstanceWithOptions(ctx, "db", StopOptions{Delay: 90 * time.Second}); err != nil {
+		t.Fatalf("StopInstanceWithOptions() error = %v", err)
+	}
+	if err := client.CancelStop(ctx, "db"); err != nil {
+		t.Fatalf("CancelStop() error = %v", err)
+	}
+
+	// multipass rejects --time together with --force, so it is never run
+	if err := client.StopInstanceWithOptions(ctx, "db", StopOptions{Delay: time.Minute, Force: true}); err == nil {
+		t.Error("Expected a delayed forced stop to be rejected")
+	}
+
+	want := []string{"stop --time 2 db", "stop --cancel db"}
+	if got := readCalls(t, calls); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected calls %q, want %q", got, want)
+	}
+}
+
+func TestGracefulStopForcesAfterGracePeriod(t *testing.T) {
+	binary, calls := writeStopBinary(t)
+	r := &InstanceResource{client: NewMultipassClient(binary)}
+
+	data := testInstanceModel("web")
+	data.StopTimeout = types.StringValue("5s")
+	data.ForceStopAfter = types.StringValue("200ms")
+
+	if err := r.gracefulStop(context.Background(), &data); err != nil {
+		t.Fatalf("gracefulStop() error = %v", err)
+	}
+
+	// stop_timeout bounds the wait and is never passed as --time
+	got := readCalls(t, calls)
+	if len(got) == 0 || got[0] != "stop web" || got[len(got)-1] != "stop --force web" {
+		t.Errorf("Expected a clean stop followed by a forced stop, got %q", got)
+	}
+}
+
+func TestGracefulStopTimeout(t *testing.T) {
+	binary, calls := writeStopBinary(t)
+	r := &InstanceResource{client: NewMultipassClient(binary)}
+
+	data := testInstanceModel("web")
+	data.StopTimeout = types.StringValue("200ms")
+
+	err := r.gracefulStop(context.Background(), &data)
+	if err == nil || !strings.Contains(err.Error(), "did not stop within stop_timeout 200ms") {
+		t.Errorf("gracefulStop() error = %v", err)
+	}
+
+	for _, call := range readCalls(t, calls) {
+		if strings.Contains(call, "--force") {
+			t.Errorf("Expected no forced stop without force_stop_after, got %q", call)
+		}
+	}
+}
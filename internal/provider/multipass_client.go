@@ -1,18 +1,68 @@
 package provider
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sh05/terraform-provider-multipass/internal/common"
 )
 
+// ErrInstanceNotFound is returned when multipass has no instance with the
+// requested name
+var ErrInstanceNotFound = errors.New("instance not found")
+
+// isInstanceNotFoundOutput reports whether multipass stderr output says that
+// the requested instance does not exist
+func isInstanceNotFoundOutput(stderr string) bool {
+	return strings.Contains(strings.ToLower(stderr), "does not exist")
+}
+
+// ErrInstanceExists is returned when a launch fails because an instance with
+// the requested name already exists
+var ErrInstanceExists = errors.New("instance already exists")
+
+// isInstanceExistsOutput reports whether multipass stderr output says that
+// an instance with the requested name already exists
+func isInstanceExistsOutput(stderr string) bool {
+	return strings.Contains(strings.ToLower(stderr), "already exists")
+}
+
 // MultipassClient wraps the Multipass CLI
 type MultipassClient struct {
-	binaryPath string
+	binaryPath  string
+	retryPolicy RetryPolicy
+	auditLog    *auditLogger
+	readCache   *instanceCache
+	defaults    common.InstanceDefaults
+	capacity    *capacityPlanner
+	stateWait   RetryPolicy
+	deleteWait  time.Duration
+
+	// version is filled in by DetectVersion
+	versionMu sync.Mutex
+	version   *common.MultipassVersion
+}
+
+// commandResult holds the captured output of a multipass invocation
+type commandResult struct {
+	Stdout []byte
+	Stderr []byte
+}
+
+// Output returns stdout and stderr combined, for use in error messages
+func (r *commandResult) Output() string {
+	return string(r.Stdout) + string(r.Stderr)
 }
 
 // NewMultipassClient creates a new Multipass client
@@ -21,15 +71,160 @@ func NewMultipassClient(binaryPath string) *MultipassClient {
 		binaryPath = "multipass"
 	}
 	return &MultipassClient{
-		binaryPath: binaryPath,
+		binaryPath:  binaryPath,
+		retryPolicy: DefaultRetryPolicy(),
+		readCache:   newInstanceCache(defaultReadCacheTTL),
+		capacity:    newCapacityPlanner(DefaultCapacityCheckConfig()),
+		stateWait:   defaultStateWaitPolicy(),
+		deleteWait:  defaultStateWaitBeforeDelete,
+	}
+}
+
+// SetRetryPolicy replaces the retry policy used for idempotent commands
+func (c *MultipassClient) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// SetAuditLogPath enables appending a JSON line per executed command to path.
+// An empty path disables the audit log.
+func (c *MultipassClient) SetAuditLogPath(path string) {
+	c.auditLog = newAuditLogger(path)
+}
+
+// SetReadCacheTTL sets how long batched instance reads are reused. A TTL of
+// zero or less disables the read cache.
+func (c *MultipassClient) SetReadCacheTTL(ttl time.Duration) {
+	c.readCache = newInstanceCache(ttl)
+}
+
+// SetInstanceDefaults sets the provider-wide defaults for new instances
+func (c *MultipassClient) SetInstanceDefaults(defaults common.InstanceDefaults) {
+	c.defaults = defaults
+}
+
+// InstanceDefaults returns the provider-wide defaults for new instances
+func (c *MultipassClient) InstanceDefaults() common.InstanceDefaults {
+	return c.defaults
+}
+
+// SetCapacityCheck replaces the settings of the plan-time host capacity check
+func (c *MultipassClient) SetCapacityCheck(config CapacityCheckConfig) {
+	c.capacity = newCapacityPlanner(config)
+}
+
+// CapacityCheck returns the settings of the plan-time host capacity check
+func (c *MultipassClient) CapacityCheck() CapacityCheckConfig {
+	return c.capacity.config
+}
+
+// HostCapacity returns the CPUs, memory and disk space of the multipass host
+func (c *MultipassClient) HostCapacity() (*HostCapacity, error) {
+	return c.capacity.Host()
+}
+
+// PlanCapacity records the allocation of an instance planned for creation
+// and returns the resources whose planned totals exceed the host capacity
+func (c *MultipassClient) PlanCapacity(name string, allocation instanceAllocation) ([]capacityUsage, error) {
+	return c.capacity.Plan(name, allocation)
+}
+
+// invalidateReadCache drops cached instance data after a mutating command
+func (c *MultipassClient) invalidateReadCache() {
+	if c.readCache != nil {
+		c.readCache.invalidate()
+	}
+}
+
+// run executes the multipass binary once with the given arguments, logging
+// the invocation through tflog and the audit log
+func (c *MultipassClient) run(ctx context.Context, args ...string) (*commandResult, error) {
+	return c.runInput(ctx, nil, args...)
+}
+
+// runInput is run with input written to the standard input of the command.
+// The input itself is never logged.
+func (c *MultipassClient) runInput(ctx context.Context, input []byte, args ...string) (*commandResult, error) {
+	var stdout, stderr bytes.Buffer
+
+	ctx = tflog.NewSubsystem(ctx, logSubsystem)
+	tflog.SubsystemDebug(ctx, logSubsystem, "executing multipass command", map[string]interface{}{
+		"binary": c.binaryPath,
+		"args":   args,
+	})
+
+	cmd := exec.CommandContext(ctx, c.binaryPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	started := time.Now()
+	err := cmd.Run()
+	duration := time.Since(started)
+
+	result := &commandResult{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}
+	exitCode := exitCodeFromError(err)
+
+	fields := map[string]interface{}{
+		"binary":      c.binaryPath,
+		"args":        args,
+		"duration_ms": duration.Milliseconds(),
+		"exit_code":   exitCode,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		fields["stderr"] = redactOutput(strings.TrimSpace(string(result.Stderr)))
+		tflog.SubsystemWarn(ctx, logSubsystem, "multipass command failed", fields)
+	} else {
+		tflog.SubsystemDebug(ctx, logSubsystem, "multipass command finished", fields)
 	}
+
+	if c.auditLog != nil {
+		record := newAuditRecord(c.binaryPath, args, started, duration, exitCode, err, strings.TrimSpace(string(result.Stderr)))
+		if auditErr := c.auditLog.Write(record); auditErr != nil {
+			tflog.SubsystemError(ctx, logSubsystem, "unable to write audit log", map[string]interface{}{
+				"path":  c.auditLog.path,
+				"error": auditErr.Error(),
+			})
+		}
+	}
+
+	return result, err
+}
+
+// exitCodeFromError extracts the process exit code from a command error.
+// It returns 0 on success and -1 when the process did not run or exit normally.
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
 }
 
 // Launch creates a new Multipass instance
-func (c *MultipassClient) Launch(opts *common.LaunchOptions) error {
+func (c *MultipassClient) Launch(ctx context.Context, opts *common.LaunchOptions) error {
+	// A failed launch may still leave an instance behind
+	defer c.invalidateReadCache()
+
+	if err := validateLaunchOptions(opts); err != nil {
+		return fmt.Errorf("invalid launch options: %w", err)
+	}
+
 	args := []string{"launch"}
 
-	if opts.Image != "" {
+	switch {
+	case opts.Blueprint != "":
+		args = append(args, opts.Blueprint)
+	case opts.Remote != "":
+		args = append(args, opts.Remote+":"+opts.Image)
+	case opts.Image != "":
 		args = append(args, opts.Image)
 	}
 
@@ -49,7 +244,14 @@ func (c *MultipassClient) Launch(opts *common.LaunchOptions) error {
 		args = append(args, "--disk", opts.Disk)
 	}
 
-	if opts.CloudInit != "" {
+	var input []byte
+	switch {
+	case opts.CloudInitData != "":
+		// Read from stdin, as a snap-confined multipass cannot read
+		// temporary files of the provider
+		args = append(args, "--cloud-init", "-")
+		input = []byte(opts.CloudInitData)
+	case opts.CloudInit != "":
 		args = append(args, "--cloud-init", opts.CloudInit)
 	}
 
@@ -62,13 +264,13 @@ func (c *MultipassClient) Launch(opts *common.LaunchOptions) error {
 		args = append(args, "--timeout", timeoutSeconds)
 	}
 
-	// Log the command being executed for debugging
-	fmt.Printf("Executing multipass command: %s %s\n", c.binaryPath, strings.Join(args, " "))
-
-	cmd := exec.Command(c.binaryPath, args...)
-	output, err := cmd.CombinedOutput()
+	// Launch is not idempotent, so it is never retried
+	result, err := c.runInput(ctx, input, args...)
 	if err != nil {
-		return fmt.Errorf("failed to launch instance: %w, output: %s", err, string(output))
+		if isInstanceExistsOutput(string(result.Stderr)) {
+			return fmt.Errorf("failed to launch instance: %w, output: %s", ErrInstanceExists, result.Output())
+		}
+		return fmt.Errorf("failed to launch instance: %w, output: %s", err, result.Output())
 	}
 
 	return nil
@@ -80,109 +282,305 @@ func (c *MultipassClient) durationToSeconds(durationStr string) (string, error)
 	if err != nil {
 		return "", err
 	}
+	if duration < 0 {
+		return "", fmt.Errorf("duration must not be negative")
+	}
 	return fmt.Sprintf("%.0f", duration.Seconds()), nil
 }
 
-// GetInstance retrieves information about a specific instance
-func (c *MultipassClient) GetInstance(name string) (*common.MultipassInstance, error) {
-	cmd := exec.Command(c.binaryPath, "info", name, "--format", "json")
-	output, err := cmd.Output()
+// GetInstance retrieves information about a specific instance. When the read
+// cache is enabled, all instances are fetched with a single `multipass info
+// --all` call and reused for subsequent reads until the cache expires.
+func (c *MultipassClient) GetInstance(ctx context.Context, name string) (*common.MultipassInstance, error) {
+	if c.readCache != nil {
+		instance, exists, err := c.readCache.lookup(ctx, name, c.getAllInstances)
+		if err == nil {
+			if exists {
+				return instance, nil
+			}
+			return nil, fmt.Errorf("%w: %s", ErrInstanceNotFound, name)
+		}
+
+		tflog.Debug(ctx, "batched instance read failed, falling back to single instance info", map[string]interface{}{
+			"name":  name,
+			"error": err.Error(),
+		})
+	}
+
+	return c.getInstance(ctx, name)
+}
+
+// getInstance retrieves a single instance with `multipass info <name>`
+func (c *MultipassClient) getInstance(ctx context.Context, name string) (*common.MultipassInstance, error) {
+	result, err := c.runWithRetry(ctx, "info", name, "--format", "json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get instance info: %w", err)
+		if isInstanceNotFoundOutput(string(result.Stderr)) {
+			return nil, fmt.Errorf("%w: %s", ErrInstanceNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get instance info: %w, output: %s", err, string(result.Stderr))
 	}
 
 	var info common.MultipassInstanceInfo
-	if err := json.Unmarshal(output, &info); err != nil {
+	if err := json.Unmarshal(result.Stdout, &info); err != nil {
 		return nil, fmt.Errorf("failed to parse instance info: %w", err)
 	}
 
 	if len(info.Errors) > 0 {
-		return nil, fmt.Errorf("multipass errors: %s", strings.Join(info.Errors, ", "))
+		errorText := strings.Join(info.Errors, ", ")
+		if isInstanceNotFoundOutput(errorText) {
+			return nil, fmt.Errorf("%w: %s", ErrInstanceNotFound, name)
+		}
+		return nil, fmt.Errorf("multipass errors: %s", errorText)
 	}
 
 	if instance, exists := info.Info[name]; exists {
+		// multipass leaves the name out of the info entries
+		if instance.Name == "" {
+			instance.Name = name
+		}
 		return &instance, nil
 	}
 
-	return nil, fmt.Errorf("instance %s not found", name)
+	return nil, fmt.Errorf("%w: %s", ErrInstanceNotFound, name)
+}
+
+// getAllInstances retrieves detailed information about every instance with
+// `multipass info --all`
+func (c *MultipassClient) getAllInstances(ctx context.Context) (map[string]common.MultipassInstance, error) {
+	result, err := c.runWithRetry(ctx, "info", "--all", "--format", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance info: %w, output: %s", err, string(result.Stderr))
+	}
+
+	var info common.MultipassInstanceInfo
+	if err := json.Unmarshal(result.Stdout, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse instance info: %w", err)
+	}
+
+	if info.Info == nil {
+		info.Info = map[string]common.MultipassInstance{}
+	}
+
+	for name, instance := range info.Info {
+		// multipass leaves the name out of the info entries
+		if instance.Name == "" {
+			instance.Name = name
+			info.Info[name] = instance
+		}
+	}
+
+	return info.Info, nil
 }
 
 // ListInstances returns all instances
-func (c *MultipassClient) ListInstances() ([]common.MultipassInstance, error) {
-	cmd := exec.Command(c.binaryPath, "list", "--format", "json")
-	output, err := cmd.Output()
+func (c *MultipassClient) ListInstances(ctx context.Context) ([]common.MultipassInstance, error) {
+	result, err := c.runWithRetry(ctx, "list", "--format", "json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to list instances: %w", err)
+		return nil, fmt.Errorf("failed to list instances: %w, output: %s", err, string(result.Stderr))
 	}
 
 	var instanceList common.MultipassInstanceList
-	if err := json.Unmarshal(output, &instanceList); err != nil {
+	if err := json.Unmarshal(result.Stdout, &instanceList); err != nil {
 		return nil, fmt.Errorf("failed to parse instance list: %w", err)
 	}
 
 	return instanceList.List, nil
 }
 
-// DeleteInstance deletes a Multipass instance
-func (c *MultipassClient) DeleteInstance(name string, purge bool) error {
-	// First delete the instance
-	cmd := exec.Command(c.binaryPath, "delete", name)
-	output, err := cmd.CombinedOutput()
+// ListInstanceDetails returns every instance with the full details reported
+// by `multipass info --all`, sorted by name
+func (c *MultipassClient) ListInstanceDetails(ctx context.Context) ([]common.MultipassInstance, error) {
+	info, err := c.getAllInstances(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete instance: %w, output: %s", err, string(output))
+		return nil, err
+	}
+
+	instances := make([]common.MultipassInstance, 0, len(info))
+	for _, instance := range info {
+		instances = append(instances, instance)
 	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
 
-	// Then purge if requested
+	return instances, nil
+}
+
+// DeleteInstance deletes a Multipass instance
+func (c *MultipassClient) DeleteInstance(ctx context.Context, name string, purge bool) error {
+	defer c.invalidateReadCache()
+
+	// A retried delete would fail on an instance that is already gone, so
+	// this is not retried. Every supported multipass release can purge just
+	// this instance, so a global `multipass purge` is never needed, which
+	// would also purge other deleted instances on the host.
+	args := []string{"delete", name}
 	if purge {
-		cmd = exec.Command(c.binaryPath, "purge")
-		output, err = cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("failed to purge instance: %w, output: %s", err, string(output))
-		}
+		args = []string{"delete", "--purge", name}
+	}
+
+	result, err := c.run(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete instance: %w, output: %s", err, result.Output())
 	}
 
 	return nil
 }
 
 // StartInstance starts a stopped instance
-func (c *MultipassClient) StartInstance(name string) error {
-	cmd := exec.Command(c.binaryPath, "start", name)
-	output, err := cmd.CombinedOutput()
+func (c *MultipassClient) StartInstance(ctx context.Context, name string) error {
+	defer c.invalidateReadCache()
+
+	result, err := c.runWithRetry(ctx, "start", name)
 	if err != nil {
-		return fmt.Errorf("failed to start instance: %w, output: %s", err, string(output))
+		return fmt.Errorf("failed to start instance: %w, output: %s", err, result.Output())
 	}
 
 	return nil
 }
 
+// StopOptions controls how StopInstanceWithOptions shuts an instance down.
+// multipass rejects a delayed forced stop, so at most one may be set.
+type StopOptions struct {
+	// Delay schedules the shutdown, rounded up to whole minutes
+	Delay time.Duration
+	// Force powers the instance off without waiting for a clean shutdown
+	Force bool
+}
+
 // StopInstance stops a running instance
-func (c *MultipassClient) StopInstance(name string) error {
-	cmd := exec.Command(c.binaryPath, "stop", name)
-	output, err := cmd.CombinedOutput()
+func (c *MultipassClient) StopInstance(ctx context.Context, name string) error {
+	return c.StopInstanceWithOptions(ctx, name, StopOptions{})
+}
+
+// StopInstanceWithOptions stops a running instance, after a delay or forcibly
+func (c *MultipassClient) StopInstanceWithOptions(ctx context.Context, name string, opts StopOptions) error {
+	if opts.Delay > 0 && opts.Force {
+		return fmt.Errorf("failed to stop instance: a delayed stop cannot be forced")
+	}
+
+	defer c.invalidateReadCache()
+
+	args := []string{"stop"}
+	if opts.Delay > 0 {
+		args = append(args, "--time", strconv.Itoa(int(math.Ceil(opts.Delay.Minutes()))))
+	}
+	if opts.Force {
+		args = append(args, "--force")
+	}
+	args = append(args, name)
+
+	result, err := c.runWithRetry(ctx, args...)
 	if err != nil {
-		return fmt.Errorf("failed to stop instance: %w, output: %s", err, string(output))
+		return fmt.Errorf("failed to stop instance: %w, output: %s", err, result.Output())
+	}
+
+	return nil
+}
+
+// CancelStop cancels a delayed shutdown of an instance
+func (c *MultipassClient) CancelStop(ctx context.Context, name string) error {
+	defer c.invalidateReadCache()
+
+	result, err := c.runWithRetry(ctx, "stop", "--cancel", name)
+	if err != nil {
+		return fmt.Errorf("failed to cancel instance stop: %w, output: %s", err, result.Output())
 	}
 
 	return nil
 }
 
 // SuspendInstance suspends a running instance
-func (c *MultipassClient) SuspendInstance(name string) error {
-	cmd := exec.Command(c.binaryPath, "suspend", name)
-	output, err := cmd.CombinedOutput()
+func (c *MultipassClient) SuspendInstance(ctx context.Context, name string) error {
+	defer c.invalidateReadCache()
+
+	result, err := c.runWithRetry(ctx, "suspend", name)
 	if err != nil {
-		return fmt.Errorf("failed to suspend instance: %w, output: %s", err, string(output))
+		return fmt.Errorf("failed to suspend instance: %w, output: %s", err, result.Output())
 	}
 
 	return nil
 }
 
 // RestartInstance restarts an instance
-func (c *MultipassClient) RestartInstance(name string) error {
-	cmd := exec.Command(c.binaryPath, "restart", name)
-	output, err := cmd.CombinedOutput()
+func (c *MultipassClient) RestartInstance(ctx context.Context, name string) error {
+	defer c.invalidateReadCache()
+
+	result, err := c.runWithRetry(ctx, "restart", name)
+	if err != nil {
+		return fmt.Errorf("failed to restart instance: %w, output: %s", err, result.Output())
+	}
+
+	return nil
+}
+
+// Exec runs a command inside an instance as the default user and returns its
+// standard output
+func (c *MultipassClient) Exec(ctx context.Context, name string, command ...string) (string, error) {
+	args := append([]string{"exec", name, "--"}, command...)
+
+	result, err := c.run(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to exec in instance: %w, output: %s", err, result.Output())
+	}
+
+	return string(result.Stdout), nil
+}
+
+// CloneInstance copies a stopped instance to a new instance called name
+func (c *MultipassClient) CloneInstance(ctx context.Context, source string, name string) error {
+	defer c.invalidateReadCache()
+
+	// A retried clone would fail on the clone created by the first attempt
+	result, err := c.run(ctx, "clone", source, "--name", name)
+	if err != nil {
+		return fmt.Errorf("failed to clone instance: %w, output: %s", err, result.Output())
+	}
+
+	return nil
+}
+
+// CreateSnapshot takes a snapshot called snapshot of a stopped instance
+func (c *MultipassClient) CreateSnapshot(ctx context.Context, name string, snapshot string) error {
+	// The snapshot count of the instance changes
+	defer c.invalidateReadCache()
+
+	result, err := c.run(ctx, "snapshot", name, "--name", snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot instance: %w, output: %s", err, result.Output())
+	}
+
+	return nil
+}
+
+// ListSnapshots returns the names of the snapshots of an instance, sorted
+func (c *MultipassClient) ListSnapshots(ctx context.Context, name string) ([]string, error) {
+	result, err := c.runWithRetry(ctx, "list", "--snapshots", "--format", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w, output: %s", err, string(result.Stderr))
+	}
+
+	var list common.MultipassSnapshotList
+	if err := json.Unmarshal(result.Stdout, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot list: %w", err)
+	}
+
+	snapshots := make([]string, 0, len(list.Info[name]))
+	for snapshot := range list.Info[name] {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Strings(snapshots)
+
+	return snapshots, nil
+}
+
+// DeleteSnapshot deletes a snapshot of an instance
+func (c *MultipassClient) DeleteSnapshot(ctx context.Context, name string, snapshot string) error {
+	defer c.invalidateReadCache()
+
+	result, err := c.run(ctx, "delete", "--purge", name+"."+snapshot)
 	if err != nil {
-		return fmt.Errorf("failed to restart instance: %w, output: %s", err, string(output))
+		return fmt.Errorf("failed to delete snapshot: %w, output: %s", err, result.Output())
 	}
 
 	return nil
@@ -1,8 +1,11 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -67,7 +70,7 @@ func TestMultipassClientWithNonExistentBinary(t *testing.T) {
 		Image: "22.04",
 	}
 	
-	err := client.Launch(opts)
+	err := client.Launch(context.Background(), opts)
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -116,7 +119,7 @@ func TestMultipassClientInvalidTimeout(t *testing.T) {
 				Timeout: tc.timeout,
 			}
 			
-			err := client.Launch(opts)
+			err := client.Launch(context.Background(), opts)
 			if tc.wantErr && err == nil {
 				t.Error("Expected error, got nil")
 			}
@@ -131,7 +134,7 @@ func TestMultipassClientInvalidTimeout(t *testing.T) {
 func TestMultipassClientGetInstanceWithNonExistentBinary(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	instance, err := client.GetInstance("test-instance")
+	instance, err := client.GetInstance(context.Background(), "test-instance")
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -148,7 +151,7 @@ func TestMultipassClientGetInstanceWithNonExistentBinary(t *testing.T) {
 func TestMultipassClientListInstancesError(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	instances, err := client.ListInstances()
+	instances, err := client.ListInstances(context.Background())
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -161,7 +164,7 @@ func TestMultipassClientListInstancesError(t *testing.T) {
 func TestMultipassClientDeleteInstanceError(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	err := client.DeleteInstance("test-instance", false)
+	err := client.DeleteInstance(context.Background(), "test-instance", false)
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -171,11 +174,30 @@ func TestMultipassClientDeleteInstanceError(t *testing.T) {
 	}
 }
 
+// TestDeleteInstancePurge tests that only the deleted instance is purged,
+// even while the multipass version is unknown
+func TestDeleteInstancePurge(t *testing.T) {
+	binary, calls := writeFakeMultipass(t, "exit 0\n")
+	client := NewMultipassClient(binary)
+
+	if err := client.DeleteInstance(context.Background(), "web", true); err != nil {
+		t.Fatalf("DeleteInstance() error = %v", err)
+	}
+	if err := client.DeleteInstance(context.Background(), "db", false); err != nil {
+		t.Fatalf("DeleteInstance() error = %v", err)
+	}
+
+	want := []string{"delete --purge web", "delete db"}
+	if got := readCalls(t, calls); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected calls %v, want %v", got, want)
+	}
+}
+
 // TestMultipassClientStartInstanceError tests StartInstance error handling
 func TestMultipassClientStartInstanceError(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	err := client.StartInstance("test-instance")
+	err := client.StartInstance(context.Background(), "test-instance")
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -189,7 +211,7 @@ func TestMultipassClientStartInstanceError(t *testing.T) {
 func TestMultipassClientStopInstanceError(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	err := client.StopInstance("test-instance")
+	err := client.StopInstance(context.Background(), "test-instance")
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -203,7 +225,7 @@ func TestMultipassClientStopInstanceError(t *testing.T) {
 func TestMultipassClientSuspendInstanceError(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	err := client.SuspendInstance("test-instance")
+	err := client.SuspendInstance(context.Background(), "test-instance")
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -217,7 +239,7 @@ func TestMultipassClientSuspendInstanceError(t *testing.T) {
 func TestMultipassClientRestartInstanceError(t *testing.T) {
 	client := NewMultipassClient("/non/existent/binary")
 	
-	err := client.RestartInstance("test-instance")
+	err := client.RestartInstance(context.Background(), "test-instance")
 	if err == nil {
 		t.Error("Expected error when using non-existent binary, got nil")
 	}
@@ -246,7 +268,7 @@ func TestMultipassClientWithPermissionError(t *testing.T) {
 		Image: "22.04",
 	}
 	
-	err = client.Launch(opts)
+	err = client.Launch(context.Background(), opts)
 	if err == nil {
 		t.Error("Expected error due to permission denied, got nil")
 	}
@@ -289,3 +311,33 @@ func TestDurationToSecondsEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestListInstanceDetails(t *testing.T) {
+	binary, _ := writeInfoBinary(t)
+	client := NewMultipassClient(binary)
+
+	instances, err := client.ListInstanceDetails(context.Background())
+	if err != nil {
+		t.Fatalf("ListInstanceDetails() error = %v", err)
+	}
+
+	if len(instances) != 2 || instances[0].Name != "web-1" || instances[1].Name != "web-2" {
+		t.Errorf("ListInstanceDetails() = %+v, want web-1 and web-2 in order", instances)
+	}
+}
+
+// TestGetInstanceNotFound tests that multipass "does not exist" errors are typed
+func TestGetInstanceNotFound(t *testing.T) {
+	binary, _ := writeFakeMultipass(t, `echo 'info failed: The following errors occurred:' >&2
+echo 'instance "missing" does not exist' >&2
+exit 2
+`)
+
+	client := NewMultipassClient(binary)
+	client.SetReadCacheTTL(0)
+
+	_, err := client.GetInstance(context.Background(), "missing")
+	if !errors.Is(err, ErrInstanceNotFound) {
+		t.Errorf("Expected ErrInstanceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// testAccFunctionsTerraformVersion is the first Terraform release with
+// provider-defined functions
+var testAccFunctionsTerraformVersion = version.Must(version.NewVersion("1.8.0"))
+
+// runFunction calls a provider function with the given arguments and returns
+// its result
+func runFunction(t *testing.T, f function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	definitionResp := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, definitionResp)
+
+	result, funcErr := definitionResp.Definition.Return.NewResultData(ctx)
+	if funcErr != nil {
+		t.Fatalf("Failed to create result data: %s", funcErr)
+	}
+
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+
+	return resp.Result.Value(), resp.Error
+}
+
+func TestParseSizeFunction(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    attr.Value
+		wantErr bool
+	}{
+		{"Gigabytes", "2G", types.Int64Value(2 << 30), false},
+		{"Decimal", "1.5G", types.Int64Value(3 << 29), false},
+		{"Binary suffix", "512MiB", types.Int64Value(512 << 20), false},
+		{"Invalid", "lots", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, funcErr := runFunction(t, NewParseSizeFunction(), types.StringValue(tc.input))
+			if (funcErr != nil) != tc.wantErr {
+				t.Fatalf("parse_size(%q) error = %v, wantErr %v", tc.input, funcErr, tc.wantErr)
+			}
+			if !tc.wantErr && !got.Equal(tc.want) {
+				t.Errorf("parse_size(%q) = %s, want %s", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAccParseSizeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(testAccFunctionsTerraformVersion),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::multipass::parse_size("1.5G")
+}
+`,
+				Check: resource.TestCheckOutput("test", "1610612736"),
+			},
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Networks primary_ipv4 can pick an address from
+const (
+	networkNAT     = "nat"
+	networkBridged = "bridged"
+)
+
+var _ function.Function = &PrimaryIPv4Function{}
+
+func NewPrimaryIPv4Function() function.Function {
+	return &PrimaryIPv4Function{}
+}
+
+// PrimaryIPv4Function picks the address of an instance on a given network.
+type PrimaryIPv4Function struct{}
+
+func (f *PrimaryIPv4Function) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "primary_ipv4"
+}
+
+func (f *PrimaryIPv4Function) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Pick the NAT or bridged IPv4 address of an instance",
+		MarkdownDescription: "Picks an address from the `ipv4` list of an instance. Multipass lists the address of the default NAT network first, followed by " +
+			"addresses on bridged networks. With `nat` the first address is returned, with `bridged` the first address after it. " +
+			"Returns null when the instance has no such address.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "addresses",
+				MarkdownDescription: "IPv4 addresses as reported by multipass",
+				ElementType:         types.StringType,
+			},
+			function.StringParameter{
+				Name:                "network",
+				MarkdownDescription: "Either `nat` or `bridged`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PrimaryIPv4Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var addresses []string
+	var network string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &addresses, &network))
+	if resp.Error != nil {
+		return
+	}
+
+	address, err := primaryIPv4(addresses, network)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	result := types.StringNull()
+	if address != "" {
+		result = types.StringValue(address)
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
+
+// primaryIPv4 returns the address on the given network, or an empty string
+// when the instance has none
+func primaryIPv4(addresses []string, network string) (string, error) {
+	var valid []string
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil && ip.To4() != nil {
+			valid = append(valid, address)
+		}
+	}
+
+	switch network {
+	case networkNAT:
+		if len(valid) > 0 {
+			return valid[0], nil
+		}
+	case networkBridged:
+		if len(valid) > 1 {
+			return valid[1], nil
+		}
+	default:
+		return "", fmt.Errorf("network must be %q or %q, got %q", networkNAT, networkBridged, network)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPrimaryIPv4Function(t *testing.T) {
+	addresses := func(values ...string) attr.Value {
+		elements := make([]attr.Value, len(values))
+		for i, value := range values {
+			elements[i] = types.StringValue(value)
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+
+	testCases := []struct {
+		name      string
+		addresses attr.Value
+		network   string
+		want      attr.Value
+		wantErr   bool
+	}{
+		{"NAT", addresses("10.0.0.2", "192.168.1.20"), "nat", types.StringValue("10.0.0.2"), false},
+		{"Bridged", addresses("10.0.0.2", "192.168.1.20"), "bridged", types.StringValue("192.168.1.20"), false},
+		{"No bridged address", addresses("10.0.0.2"), "bridged", types.StringNull(), false},
+		{"No addresses", addresses(), "nat", types.StringNull(), false},
+		{"Skips invalid entries", addresses("N/A", "10.0.0.2"), "nat", types.StringValue("10.0.0.2"), false},
+		{"Unknown network", addresses("10.0.0.2"), "host", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, funcErr := runFunction(t, NewPrimaryIPv4Function(), tc.addresses, types.StringValue(tc.network))
+			if (funcErr != nil) != tc.wantErr {
+				t.Fatalf("primary_ipv4 error = %v, wantErr %v", funcErr, tc.wantErr)
+			}
+			if !tc.wantErr && !got.Equal(tc.want) {
+				t.Errorf("primary_ipv4 = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAccPrimaryIPv4Function(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(testAccFunctionsTerraformVersion),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::multipass::primary_ipv4(["10.0.0.2", "192.168.1.20"], "bridged")
+}
+`,
+				Check: resource.TestCheckOutput("test", "192.168.1.20"),
+			},
+		},
+	})
+}
@@ -2,18 +2,28 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
 )
 
 // Ensure MultipassProvider satisfies various provider interfaces.
 var _ provider.Provider = &MultipassProvider{}
 var _ provider.ProviderWithFunctions = &MultipassProvider{}
+var _ provider.ProviderWithEphemeralResources = &MultipassProvider{}
+var _ provider.ProviderWithListResources = &MultipassProvider{}
 
 // MultipassProvider defines the provider implementation.
 type MultipassProvider struct {
@@ -24,7 +34,38 @@ type MultipassProvider struct {
 
 // MultipassProviderModel describes the provider data model.
 type MultipassProviderModel struct {
-	BinaryPath types.String `tfsdk:"binary_path"`
+	BinaryPath    types.String        `tfsdk:"binary_path"`
+	AuditLogPath  types.String        `tfsdk:"audit_log_path"`
+	ReadCacheTTL  types.String        `tfsdk:"read_cache_ttl"`
+	Retry         *RetryProviderModel `tfsdk:"retry"`
+	Defaults      *DefaultsModel      `tfsdk:"defaults"`
+	CapacityCheck *CapacityCheckModel `tfsdk:"capacity_check"`
+}
+
+// CapacityCheckModel describes the capacity_check block of the provider.
+type CapacityCheckModel struct {
+	Mode                  types.String  `tfsdk:"mode"`
+	CPUOvercommitRatio    types.Float64 `tfsdk:"cpu_overcommit_ratio"`
+	MemoryOvercommitRatio types.Float64 `tfsdk:"memory_overcommit_ratio"`
+	DiskOvercommitRatio   types.Float64 `tfsdk:"disk_overcommit_ratio"`
+	StoragePath           types.String  `tfsdk:"storage_path"`
+}
+
+// DefaultsModel describes the defaults block of the provider.
+type DefaultsModel struct {
+	Image      types.String `tfsdk:"image"`
+	CPU        types.String `tfsdk:"cpu"`
+	Memory     types.String `tfsdk:"memory"`
+	Disk       types.String `tfsdk:"disk"`
+	CloudInit  types.String `tfsdk:"cloud_init"`
+	NamePrefix types.String `tfsdk:"name_prefix"`
+}
+
+// RetryProviderModel describes the retry block of the provider.
+type RetryProviderModel struct {
+	MaxAttempts    types.Int64  `tfsdk:"max_attempts"`
+	InitialBackoff types.String `tfsdk:"initial_backoff"`
+	MaxBackoff     types.String `tfsdk:"max_backoff"`
 }
 
 func (p *MultipassProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -40,6 +81,87 @@ func (p *MultipassProvider) Schema(ctx context.Context, req provider.SchemaReque
 				MarkdownDescription: "Path to the multipass binary. Defaults to 'multipass' if not specified.",
 				Optional:            true,
 			},
+			"audit_log_path": schema.StringAttribute{
+				MarkdownDescription: "Path to a file that every executed multipass command is appended to as a JSON line (time, arguments, duration, exit code and redacted stderr). Disabled when not set.",
+				Optional:            true,
+			},
+			"read_cache_ttl": schema.StringAttribute{
+				MarkdownDescription: "How long the details of all instances, fetched with a single `multipass info --all` call, are reused for instance reads (e.g. '10s'). Any create, delete or power state change clears the cache. Set to '0s' to disable caching. Defaults to '10s'.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				MarkdownDescription: "Retry policy for idempotent multipass commands (such as `info`, `list`, `start` and `stop`) that fail with transient daemon errors, e.g. when the multipass socket is unavailable right after a daemon restart.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of attempts per command, including the first one. Set to 1 to disable retries. Defaults to 3.",
+						Optional:            true,
+					},
+					"initial_backoff": schema.StringAttribute{
+						MarkdownDescription: "Delay before the first retry (e.g. '1s', '500ms'). The delay doubles after each attempt. Defaults to '1s'.",
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "Upper bound for the delay between retries (e.g. '10s'). Defaults to '10s'.",
+						Optional:            true,
+					},
+				},
+			},
+			"defaults": schema.SingleNestedBlock{
+				MarkdownDescription: "Default settings for `multipass_instance` resources that do not set them. Defaults only apply when an instance is created, so changing them does not replace existing instances.",
+				Attributes: map[string]schema.Attribute{
+					"image": schema.StringAttribute{
+						MarkdownDescription: "Default image (e.g. '22.04')",
+						Optional:            true,
+					},
+					"cpu": schema.StringAttribute{
+						MarkdownDescription: "Default number of CPUs",
+						Optional:            true,
+					},
+					"memory": schema.StringAttribute{
+						MarkdownDescription: "Default amount of memory (e.g. '2G')",
+						Optional:            true,
+					},
+					"disk": schema.StringAttribute{
+						MarkdownDescription: "Default disk size (e.g. '10G')",
+						Optional:            true,
+					},
+					"cloud_init": schema.StringAttribute{
+						MarkdownDescription: "Default path to a cloud-init configuration file",
+						Optional:            true,
+					},
+					"name_prefix": schema.StringAttribute{
+						MarkdownDescription: "Prefix for generated instance names. Instances without a `name` get this prefix followed by 8 random hexadecimal characters.",
+						Optional:            true,
+					},
+				},
+			},
+			"capacity_check": schema.SingleNestedBlock{
+				MarkdownDescription: "Plan-time check that the CPUs, memory and disk of all `multipass_instance` resources being created fit on the host. Omitted settings use multipass defaults (1 CPU, 1G memory, 5G disk).",
+				Attributes: map[string]schema.Attribute{
+					"mode": schema.StringAttribute{
+						MarkdownDescription: "What to do when planned instances exceed the host capacity: 'warn', 'error' or 'off'. Defaults to 'warn'.",
+						Optional:            true,
+					},
+					"cpu_overcommit_ratio": schema.Float64Attribute{
+						MarkdownDescription: "Multiple of the host CPU count that planned instances may use. Defaults to 1.0.",
+						Optional:            true,
+					},
+					"memory_overcommit_ratio": schema.Float64Attribute{
+						MarkdownDescription: "Multiple of the available host memory that planned instances may use. Defaults to 1.0.",
+						Optional:            true,
+					},
+					"disk_overcommit_ratio": schema.Float64Attribute{
+						MarkdownDescription: "Multiple of the free disk space in the multipass storage directory that planned instances may use. Defaults to 1.0.",
+						Optional:            true,
+					},
+					"storage_path": schema.StringAttribute{
+						MarkdownDescription: "Directory where multipass stores instance images, used to measure free disk space. Defaults to the platform location of the multipass daemon data.",
+						Optional:            true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -56,29 +178,184 @@ func (p *MultipassProvider) Configure(ctx context.Context, req provider.Configur
 	// Configuration values are now available.
 	binaryPath := data.BinaryPath.ValueString()
 
+	retryPolicy, diags := retryPolicyFromModel(data.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readCacheTTL := defaultReadCacheTTL
+	if !data.ReadCacheTTL.IsNull() && !data.ReadCacheTTL.IsUnknown() {
+		ttl, err := time.ParseDuration(data.ReadCacheTTL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("read_cache_ttl"), "Invalid Read Cache Configuration",
+				fmt.Sprintf("Unable to parse read_cache_ttl: %s", err))
+			return
+		}
+		readCacheTTL = ttl
+	}
+
+	capacityCheck, diags := capacityCheckFromModel(data.CapacityCheck)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create the Multipass client
 	client := NewMultipassClient(binaryPath)
+	client.SetRetryPolicy(retryPolicy)
+	client.SetAuditLogPath(data.AuditLogPath.ValueString())
+	client.SetReadCacheTTL(readCacheTTL)
+	client.SetInstanceDefaults(instanceDefaultsFromModel(data.Defaults))
+	client.SetCapacityCheck(capacityCheck)
+
+	// Detect the installed multipass release so features can be gated on it
+	version, err := client.DetectVersion(ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to Detect Multipass Version",
+			fmt.Sprintf("Features that depend on the multipass release will not be available: %s", err))
+	} else {
+		tflog.Info(ctx, "detected multipass version", map[string]interface{}{
+			"client": version.Client,
+			"daemon": version.Daemon,
+		})
+	}
 
 	// Make the client available during resource operations
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
+	resp.ListResourceData = client
+}
+
+// retryPolicyFromModel builds a RetryPolicy from the provider retry block,
+// falling back to the defaults for any unset value
+func retryPolicyFromModel(model *RetryProviderModel) (RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := DefaultRetryPolicy()
+	if model == nil {
+		return policy, diags
+	}
+
+	if !model.MaxAttempts.IsNull() && !model.MaxAttempts.IsUnknown() {
+		policy.MaxAttempts = int(model.MaxAttempts.ValueInt64())
+	}
+
+	if !model.InitialBackoff.IsNull() && !model.InitialBackoff.IsUnknown() {
+		backoff, err := time.ParseDuration(model.InitialBackoff.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("retry").AtName("initial_backoff"), "Invalid Retry Configuration",
+				fmt.Sprintf("Unable to parse initial_backoff: %s", err))
+		}
+		policy.InitialBackoff = backoff
+	}
+
+	if !model.MaxBackoff.IsNull() && !model.MaxBackoff.IsUnknown() {
+		backoff, err := time.ParseDuration(model.MaxBackoff.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("retry").AtName("max_backoff"), "Invalid Retry Configuration",
+				fmt.Sprintf("Unable to parse max_backoff: %s", err))
+		}
+		policy.MaxBackoff = backoff
+	} else if policy.InitialBackoff > policy.MaxBackoff {
+		// Only initial_backoff was raised, so let the cap follow it
+		policy.MaxBackoff = policy.InitialBackoff
+	}
+
+	if diags.HasError() {
+		return policy, diags
+	}
+
+	if err := policy.Validate(); err != nil {
+		diags.AddAttributeError(path.Root("retry"), "Invalid Retry Configuration", err.Error())
+	}
+
+	return policy, diags
+}
+
+// instanceDefaultsFromModel converts the provider defaults block
+func instanceDefaultsFromModel(model *DefaultsModel) common.InstanceDefaults {
+	if model == nil {
+		return common.InstanceDefaults{}
+	}
+
+	return common.InstanceDefaults{
+		Image:      model.Image.ValueString(),
+		CPU:        model.CPU.ValueString(),
+		Memory:     model.Memory.ValueString(),
+		Disk:       model.Disk.ValueString(),
+		CloudInit:  model.CloudInit.ValueString(),
+		NamePrefix: model.NamePrefix.ValueString(),
+	}
+}
+
+// capacityCheckFromModel builds the capacity check settings from the provider
+// capacity_check block, falling back to the defaults for any unset value
+func capacityCheckFromModel(model *CapacityCheckModel) (CapacityCheckConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := DefaultCapacityCheckConfig()
+	if model == nil {
+		return config, diags
+	}
+
+	if !model.Mode.IsNull() && !model.Mode.IsUnknown() {
+		config.Mode = model.Mode.ValueString()
+	}
+	if !model.CPUOvercommitRatio.IsNull() && !model.CPUOvercommitRatio.IsUnknown() {
+		config.CPUOvercommitRatio = model.CPUOvercommitRatio.ValueFloat64()
+	}
+	if !model.MemoryOvercommitRatio.IsNull() && !model.MemoryOvercommitRatio.IsUnknown() {
+		config.MemoryOvercommitRatio = model.MemoryOvercommitRatio.ValueFloat64()
+	}
+	if !model.DiskOvercommitRatio.IsNull() && !model.DiskOvercommitRatio.IsUnknown() {
+		config.DiskOvercommitRatio = model.DiskOvercommitRatio.ValueFloat64()
+	}
+	config.StoragePath = model.StoragePath.ValueString()
+
+	if err := config.Validate(); err != nil {
+		diags.AddAttributeError(path.Root("capacity_check"), "Invalid Capacity Check Configuration", err.Error())
+	}
+
+	return config, diags
 }
 
 func (p *MultipassProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewInstanceResource,
+		NewAliasResource,
+	}
+}
+
+func (p *MultipassProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewSSHSessionEphemeralResource,
+	}
+}
+
+func (p *MultipassProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewInstanceListResource,
 	}
 }
 
 func (p *MultipassProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewInstanceDataSource,
+		NewVersionDataSource,
+		NewHostDataSource,
+		NewAliasesDataSource,
 	}
 }
 
 func (p *MultipassProvider) Functions(ctx context.Context) []func() function.Function {
 	return []func() function.Function{
-		// No functions defined yet
+		NewParseSizeFunction,
+		NewFormatSizeFunction,
+		NewValidInstanceNameFunction,
+		NewPrimaryIPv4Function,
+		NewCloudInitMergeFunction,
 	}
 }
 
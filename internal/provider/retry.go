@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Default retry settings applied when the provider has no retry block
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 1 * time.Second
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// retriableErrorPatterns are lower-cased fragments of multipass stderr output
+// that indicate a transient daemon condition rather than a real failure
+var retriableErrorPatterns = []string{
+	"cannot connect to the multipass socket",
+	"failed to connect to",
+	"connection refused",
+	"socket closed",
+	"instance is busy",
+}
+
+// RetryPolicy controls how idempotent multipass commands are retried
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+}
+
+// Validate checks that the policy values are usable
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("max_attempts must be at least 1, got %d", p.MaxAttempts)
+	}
+	if p.InitialBackoff < 0 {
+		return fmt.Errorf("initial_backoff must not be negative, got %s", p.InitialBackoff)
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		return fmt.Errorf("max_backoff (%s) must not be less than initial_backoff (%s)", p.MaxBackoff, p.InitialBackoff)
+	}
+	return nil
+}
+
+// backoff returns the delay before the given retry (1-based), doubling from
+// InitialBackoff and capped at MaxBackoff
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// isRetriableError reports whether multipass stderr output describes a
+// transient daemon error worth retrying
+func isRetriableError(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, pattern := range retriableErrorPatterns {
+		if strings.Contains(stderr, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// runWithRetry runs an idempotent multipass command, retrying it according to
+// the client's retry policy while stderr reports a transient error
+func (c *MultipassClient) runWithRetry(ctx context.Context, args ...string) (*commandResult, error) {
+	policy := c.retryPolicy
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	ctx = tflog.NewSubsystem(ctx, logSubsystem)
+
+	for attempt := 1; ; attempt++ {
+		result, err := c.run(ctx, args...)
+		if err == nil {
+			return result, nil
+		}
+
+		if attempt >= policy.MaxAttempts || !isRetriableError(string(result.Stderr)) {
+			return result, err
+		}
+
+		delay := policy.backoff(attempt)
+		tflog.SubsystemWarn(ctx, logSubsystem, "transient multipass error, retrying", map[string]interface{}{
+			"args":         args,
+			"attempt":      attempt,
+			"max_attempts": policy.MaxAttempts,
+			"backoff":      delay.String(),
+			"error":        redactOutput(strings.TrimSpace(string(result.Stderr))),
+		})
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(delay):
+		}
+	}
+}
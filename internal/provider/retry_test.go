@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestIsRetriableError tests classification of multipass stderr output
+func TestIsRetriableError(t *testing.T) {
+	testCases := []struct {
+		name   string
+		stderr string
+		want   bool
+	}{
+		{"Socket unavailable", "cannot connect to the multipass socket\nPlease ensure multipassd is running", true},
+		{"Mixed case", "Cannot Connect To The Multipass Socket", true},
+		{"Instance busy", "start failed: instance is busy", true},
+		{"Connection refused", "failed to connect to 'unix:/run/multipass_socket': Connection refused", true},
+		{"Instance missing", `info failed: instance "foo" does not exist`, false},
+		{"Invalid argument", "error: invalid value for --cpus", false},
+		{"Empty", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isRetriableError(tc.stderr); got != tc.want {
+				t.Errorf("isRetriableError(%q) = %v, want %v", tc.stderr, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestRetryPolicyBackoff tests exponential backoff capping
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, want)
+		}
+	}
+}
+
+// TestRetryPolicyValidate tests validation of retry policy values
+func TestRetryPolicyValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		{"Default", DefaultRetryPolicy(), false},
+		{"Single attempt", RetryPolicy{MaxAttempts: 1}, false},
+		{"Zero attempts", RetryPolicy{MaxAttempts: 0}, true},
+		{"Negative backoff", RetryPolicy{MaxAttempts: 2, InitialBackoff: -time.Second}, true},
+		{"Max below initial", RetryPolicy{MaxAttempts: 2, InitialBackoff: 2 * time.Second, MaxBackoff: time.Second}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.wantErr && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestRetryPolicyFromModel tests conversion of the provider retry block
+func TestRetryPolicyFromModel(t *testing.T) {
+	policy, diags := retryPolicyFromModel(nil)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if policy != DefaultRetryPolicy() {
+		t.Errorf("Expected default policy, got %+v", policy)
+	}
+
+	policy, diags = retryPolicyFromModel(&RetryProviderModel{
+		MaxAttempts:    types.Int64Value(5),
+		InitialBackoff: types.StringValue("30s"),
+		MaxBackoff:     types.StringNull(),
+	})
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if policy.MaxAttempts != 5 || policy.InitialBackoff != 30*time.Second || policy.MaxBackoff != 30*time.Second {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+
+	_, diags = retryPolicyFromModel(&RetryProviderModel{
+		MaxAttempts:    types.Int64Null(),
+		InitialBackoff: types.StringValue("soon"),
+		MaxBackoff:     types.StringNull(),
+	})
+	if !diags.HasError() {
+		t.Error("Expected error for invalid initial_backoff")
+	}
+}
+
+// writeFlakyBinary creates a fake multipass that fails with the given stderr
+// until it has been invoked failures times, then prints stdout
+func writeFlakyBinary(t *testing.T, failures int, stderr string, stdout string) (string, string) {
+	t.Helper()
+
+	return writeFakeMultipass(t, fmt.Sprintf(`n=$(grep -c '' "$calls")
+if [ $n -le %d ]; then echo '%s' >&2; exit 2; fi
+echo '%s'
+`, failures, stderr, stdout))
+}
+
+// TestRunWithRetryRecoversFromTransientError tests that transient errors are retried
+func TestRunWithRetryRecoversFromTransientError(t *testing.T) {
+	binary, calls := writeFlakyBinary(t, 2, "cannot connect to the multipass socket", `{"list": []}`)
+
+	client := NewMultipassClient(binary)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	instances, err := client.ListInstances(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("Expected no instances, got %d", len(instances))
+	}
+	if got := len(readCalls(t, calls)); got != 3 {
+		t.Errorf("Expected 3 invocations, got %d", got)
+	}
+}
+
+// TestRunWithRetryGivesUp tests that retries stop at max_attempts
+func TestRunWithRetryGivesUp(t *testing.T) {
+	binary, calls := writeFlakyBinary(t, 5, "instance is busy", `{"list": []}`)
+
+	client := NewMultipassClient(binary)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	err := client.StartInstance(context.Background(), "test-instance")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "instance is busy") {
+		t.Errorf("Expected error to contain stderr, got: %s", err.Error())
+	}
+	if got := len(readCalls(t, calls)); got != 2 {
+		t.Errorf("Expected 2 invocations, got %d", got)
+	}
+}
+
+// TestRunWithRetrySkipsPermanentError tests that non-transient errors fail immediately
+func TestRunWithRetrySkipsPermanentError(t *testing.T) {
+	binary, calls := writeFlakyBinary(t, 5, `instance "test-instance" does not exist`, `{"list": []}`)
+
+	client := NewMultipassClient(binary)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	if err := client.StopInstance(context.Background(), "test-instance"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if got := len(readCalls(t, calls)); got != 1 {
+		t.Errorf("Expected 1 invocation, got %d", got)
+	}
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// sizePattern matches multipass size strings such as "512M", "1.5G", "10GiB" or "1073741824"
+var sizePattern = regexp.MustCompile(`^(-?)(\d+)(?:\.(\d+))?\s*([A-Za-z]*)$`)
+
+// sizeUnits maps multipass size units to their multiplier. Multipass uses
+// binary multiples, so "1G" is 1024^3 bytes.
+var sizeUnits = map[string]int64{
+	"":  1,
+	"B": 1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// parseSize converts a multipass size string to bytes. Units K, M, G and T
+// may be followed by "B" or "iB" and are case-insensitive.
+func parseSize(size string) (int64, error) {
+	size = strings.TrimSpace(size)
+	if size == "" {
+		return 0, fmt.Errorf("size cannot be empty")
+	}
+
+	matches := sizePattern.FindStringSubmatch(size)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid format %q, expected a number with an optional unit such as 512M or 2G", size)
+	}
+
+	if matches[1] == "-" {
+		return 0, fmt.Errorf("size must be positive")
+	}
+
+	unit := strings.ToUpper(matches[4])
+	if len(unit) > 1 {
+		unit = strings.TrimSuffix(strings.TrimSuffix(unit, "IB"), "B")
+		if unit == "" {
+			unit = "B"
+		}
+	}
+
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit %q, expected one of K, M, G or T", matches[4])
+	}
+
+	number := matches[2]
+	if matches[3] != "" {
+		number += "." + matches[3]
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid format %q: %w", size, err)
+	}
+
+	bytes := value * float64(multiplier)
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", size)
+	}
+
+	return int64(bytes), nil
+}
+
+// formatSize converts bytes to the shortest multipass size string that
+// represents them exactly, e.g. 1610612736 becomes "1536M"
+func formatSize(bytes int64) (string, error) {
+	if bytes < 0 {
+		return "", fmt.Errorf("size must not be negative")
+	}
+	if bytes == 0 {
+		return "0", nil
+	}
+
+	for _, unit := range []string{"T", "G", "M", "K"} {
+		multiplier := sizeUnits[unit]
+		if bytes%multiplier == 0 {
+			return strconv.FormatInt(bytes/multiplier, 10) + unit, nil
+		}
+	}
+
+	return strconv.FormatInt(bytes, 10), nil
+}
+
+// normalizeSize rewrites a size attribute in the form formatSize produces,
+// e.g. "1024M" becomes "1G". Null, unknown and unparsable values are kept.
+func normalizeSize(value types.String) types.String {
+	if value.IsNull() || value.IsUnknown() {
+		return value
+	}
+	bytes, err := parseSize(value.ValueString())
+	if err != nil {
+		return value
+	}
+	size, err := formatSize(bytes)
+	if err != nil {
+		return value
+	}
+	return types.StringValue(size)
+}
+
+var _ planmodifier.String = equivalentSizeModifier{}
+
+// equivalentSizeModifier keeps the size in state when the configuration
+// spells the same number of bytes differently, e.g. "1024M" for "1G", so
+// that only a real change of size replaces the instance
+type equivalentSizeModifier struct{}
+
+func (m equivalentSizeModifier) Description(ctx context.Context) string {
+	return "keeps the prior size when the planned size is the same number of bytes"
+}
+
+func (m equivalentSizeModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m equivalentSizeModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.StateValue.IsUnknown() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	prior, err := parseSize(req.StateValue.ValueString())
+	if err != nil {
+		return
+	}
+	planned, err := parseSize(req.PlanValue.ValueString())
+	if err != nil {
+		return
+	}
+
+	if prior == planned {
+		resp.PlanValue = req.StateValue
+	}
+}
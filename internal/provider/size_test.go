@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestParseSize tests conversion of multipass size strings to bytes
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    int64
+		wantErr string
+	}{
+		{"Bytes", "1073741824", 1 << 30, ""},
+		{"Kilobytes", "512K", 512 << 10, ""},
+		{"Megabytes", "512M", 512 << 20, ""},
+		{"Gigabytes", "2G", 2 << 30, ""},
+		{"Terabytes", "1T", 1 << 40, ""},
+		{"Byte suffix", "2GB", 2 << 30, ""},
+		{"Binary suffix", "2GiB", 2 << 30, ""},
+		{"Lowercase", "2g", 2 << 30, ""},
+		{"Decimal", "1.5G", 3 << 29, ""},
+		{"Explicit bytes", "100B", 100, ""},
+		{"Empty", "", 0, "cannot be empty"},
+		{"Not a number", "lots", 0, "invalid format"},
+		{"Negative", "-1G", 0, "must be positive"},
+		{"Unknown unit", "1X", 0, "invalid unit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSize(tc.input)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("parseSize(%q) = %d, want %d", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestFormatSize tests conversion of bytes to multipass size strings
+func TestFormatSize(t *testing.T) {
+	testCases := []struct {
+		input   int64
+		want    string
+		wantErr bool
+	}{
+		{0, "0", false},
+		{1 << 30, "1G", false},
+		{3 << 29, "1536M", false},
+		{2 << 40, "2T", false},
+		{1000, "1000", false},
+		{4096, "4K", false},
+		{-1, "", true},
+	}
+
+	for _, tc := range testCases {
+		got, err := formatSize(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("formatSize(%d) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tc.input, got, tc.want)
+		}
+
+		// Formatted sizes must parse back to the same value
+		if !tc.wantErr && tc.input > 0 {
+			if parsed, err := parseSize(got); err != nil || parsed != tc.input {
+				t.Errorf("parseSize(%q) = %d, %v, want %d", got, parsed, err, tc.input)
+			}
+		}
+	}
+}
+
+// TestEquivalentSizeModifier tests that a differently spelled size keeps
+// the state value while a different size is planned as configured
+func TestEquivalentSizeModifier(t *testing.T) {
+	testCases := []struct {
+		state, plan types.String
+		want        types.String
+	}{
+		{types.StringValue("1G"), types.StringValue("1024M"), types.StringValue("1G")},
+		{types.StringValue("1536M"), types.StringValue("1.5GiB"), types.StringValue("1536M")},
+		{types.StringValue("1G"), types.StringValue("2G"), types.StringValue("2G")},
+		{types.StringNull(), types.StringValue("1G"), types.StringValue("1G")},
+		{types.StringValue("lots"), types.StringValue("1G"), types.StringValue("1G")},
+	}
+
+	for _, tc := range testCases {
+		req := planmodifier.StringRequest{StateValue: tc.state, PlanValue: tc.plan}
+		resp := &planmodifier.StringResponse{PlanValue: tc.plan}
+		equivalentSizeModifier{}.PlanModifyString(context.Background(), req, resp)
+		if !resp.PlanValue.Equal(tc.want) {
+			t.Errorf("Planned %s over %s as %s, want %s", tc.plan, tc.state, resp.PlanValue, tc.want)
+		}
+	}
+}
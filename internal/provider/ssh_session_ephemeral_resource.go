@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshSessionUser is the account multipass exec runs as and whose
+// authorized_keys receives the session key
+const sshSessionUser = "ubuntu"
+
+// sshSessionPrivateKey is the private state key holding what Close needs to
+// remove the session key again
+const sshSessionPrivateKey = "session"
+
+// Shell snippets run inside the instance. The key or its comment is passed
+// as $1 so it is never interpreted by the shell.
+const (
+	installAuthorizedKeyScript = `mkdir -p ~/.ssh && chmod 700 ~/.ssh && printf '%s\n' "$1" >> ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys`
+	removeAuthorizedKeyScript  = `[ ! -f ~/.ssh/authorized_keys ] || { grep -v -F -e "$1" ~/.ssh/authorized_keys > ~/.ssh/authorized_keys.tmp; cat ~/.ssh/authorized_keys.tmp > ~/.ssh/authorized_keys; rm -f ~/.ssh/authorized_keys.tmp; }`
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &SSHSessionEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &SSHSessionEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &SSHSessionEphemeralResource{}
+
+func NewSSHSessionEphemeralResource() ephemeral.EphemeralResource {
+	return &SSHSessionEphemeralResource{}
+}
+
+// SSHSessionEphemeralResource defines the ephemeral resource implementation.
+type SSHSessionEphemeralResource struct {
+	client *MultipassClient
+}
+
+// SSHSessionEphemeralResourceModel describes the ephemeral resource data model.
+type SSHSessionEphemeralResourceModel struct {
+	Instance   types.String `tfsdk:"instance"`
+	Host       types.String `tfsdk:"host"`
+	User       types.String `tfsdk:"user"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	PublicKey  types.String `tfsdk:"public_key"`
+}
+
+// sshSessionState is stored in private state between Open and Close
+type sshSessionState struct {
+	Instance string `json:"instance"`
+	Comment  string `json:"comment"`
+}
+
+func (r *SSHSessionEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_session"
+}
+
+func (r *SSHSessionEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Temporary SSH access to a running Multipass instance. A new ed25519 key pair is generated and its public key is added to the `ubuntu` user's authorized_keys with `multipass exec`. The key is removed again when Terraform closes the ephemeral resource, and the private key is never written to state. Requires Terraform 1.10 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				MarkdownDescription: "Name of the running instance to connect to",
+				Required:            true,
+				Validators: []validator.String{
+					instanceNameValidator(),
+				},
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "First IPv4 address of the instance",
+				Computed:            true,
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "User the key is authorized for",
+				Computed:            true,
+			},
+			"private_key": schema.StringAttribute{
+				MarkdownDescription: "Private key in OpenSSH format",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"public_key": schema.StringAttribute{
+				MarkdownDescription: "Public key in authorized_keys format",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SSHSessionEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*MultipassClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *MultipassClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SSHSessionEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data SSHSessionEphemeralResourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Instance.ValueString()
+
+	instance, err := r.client.GetInstance(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read instance, got error: %s", err))
+		return
+	}
+
+	if len(instance.IPv4) == 0 {
+		resp.Diagnostics.AddError("Instance Not Reachable",
+			fmt.Sprintf("Instance %s has no IPv4 address (state %s). It must be running to open an SSH session.", name, instance.State))
+		return
+	}
+
+	privateKey, publicKey, comment, err := generateSessionKey()
+	if err != nil {
+		resp.Diagnostics.AddError("Key Generation Error", fmt.Sprintf("Unable to generate SSH key, got error: %s", err))
+		return
+	}
+
+	if _, err := r.client.Exec(ctx, name, "sh", "-c", installAuthorizedKeyScript, "sh", publicKey); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to install SSH key, got error: %s", err))
+		return
+	}
+
+	tflog.Debug(ctx, "installed ssh session key", map[string]interface{}{
+		"instance": name,
+		"comment":  comment,
+	})
+
+	session, err := json.Marshal(sshSessionState{Instance: name, Comment: comment})
+	if err != nil {
+		resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to encode session state, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, sshSessionPrivateKey, session)...)
+
+	data.Host = types.StringValue(instance.IPv4[0])
+	data.User = types.StringValue(sshSessionUser)
+	data.PrivateKey = types.StringValue(privateKey)
+	data.PublicKey = types.StringValue(publicKey)
+
+	// Save data into the ephemeral result
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *SSHSessionEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, sshSessionPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || raw == nil {
+		return
+	}
+
+	var session sshSessionState
+	if err := json.Unmarshal(raw, &session); err != nil {
+		resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to decode session state, got error: %s", err))
+		return
+	}
+
+	if _, err := r.client.Exec(ctx, session.Instance, "sh", "-c", removeAuthorizedKeyScript, "sh", session.Comment); err != nil {
+		resp.Diagnostics.AddWarning("Unable to Remove SSH Key",
+			fmt.Sprintf("The session key %s could not be removed from instance %s: %s", session.Comment, session.Instance, err))
+		return
+	}
+
+	tflog.Debug(ctx, "removed ssh session key", map[string]interface{}{
+		"instance": session.Instance,
+		"comment":  session.Comment,
+	})
+}
+
+// generateSessionKey creates an ed25519 key pair. The public key carries a
+// random comment that identifies it in authorized_keys.
+func generateSessionKey() (privateKey string, publicKey string, comment string, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", "", "", err
+	}
+	comment = "terraform-multipass-session-" + hex.EncodeToString(suffix)
+
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	sshPublic, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	publicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublic))) + " " + comment
+
+	return string(pem.EncodeToMemory(block)), publicKey, comment, nil
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// writeExecBinary creates a fake multipass binary that runs `exec` commands
+// locally with HOME pointing at a temporary directory, and returns the
+// binary and that home directory
+func writeExecBinary(t *testing.T) (string, string) {
+	t.Helper()
+
+	home := filepath.Join(t.TempDir(), "home")
+	if err := os.Mkdir(home, 0755); err != nil {
+		t.Fatalf("Failed to create home: %v", err)
+	}
+
+	binary, _ := writeFakeMultipass(t, fmt.Sprintf(`[ "$1" = "exec" ] || exit 2
+shift 2
+[ "$1" = "--" ] && shift
+HOME=%s exec "$@"
+`, home))
+	return binary, home
+}
+
+func TestGenerateSessionKey(t *testing.T) {
+	privateKey, publicKey, comment, err := generateSessionKey()
+	if err != nil {
+		t.Fatalf("generateSessionKey() error = %v", err)
+	}
+
+	if !strings.HasPrefix(comment, "terraform-multipass-session-") {
+		t.Errorf("unexpected comment %q", comment)
+	}
+	if !strings.HasPrefix(publicKey, "ssh-ed25519 ") || !strings.HasSuffix(publicKey, " "+comment) {
+		t.Errorf("unexpected public key %q", publicKey)
+	}
+
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		t.Fatalf("Failed to parse private key: %v", err)
+	}
+
+	authorized, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		t.Fatalf("Failed to parse public key: %v", err)
+	}
+	if string(signer.PublicKey().Marshal()) != string(authorized.Marshal()) {
+		t.Error("public key does not belong to the private key")
+	}
+}
+
+func TestSessionKeyInstallAndRemove(t *testing.T) {
+	binary, home := writeExecBinary(t)
+	client := NewMultipassClient(binary)
+	ctx := context.Background()
+
+	authorizedKeys := filepath.Join(home, ".ssh", "authorized_keys")
+	if err := os.MkdirAll(filepath.Dir(authorizedKeys), 0700); err != nil {
+		t.Fatalf("Failed to create .ssh: %v", err)
+	}
+	existing := "ssh-ed25519 AAAAexisting user@laptop\n"
+	if err := os.WriteFile(authorizedKeys, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to write authorized_keys: %v", err)
+	}
+
+	_, publicKey, comment, err := generateSessionKey()
+	if err != nil {
+		t.Fatalf("generateSessionKey() error = %v", err)
+	}
+
+	if _, err := client.Exec(ctx, "web", "sh", "-c", installAuthorizedKeyScript, "sh", publicKey); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(authorizedKeys)
+	if string(content) != existing+publicKey+"\n" {
+		t.Errorf("authorized_keys after install = %q", content)
+	}
+
+	if _, err := client.Exec(ctx, "web", "sh", "-c", removeAuthorizedKeyScript, "sh", comment); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+
+	content, _ = os.ReadFile(authorizedKeys)
+	if string(content) != existing {
+		t.Errorf("authorized_keys after remove = %q, want %q", content, existing)
+	}
+}
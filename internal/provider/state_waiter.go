@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Polling intervals used while an instance is in a transitional state
+const (
+	defaultStateWaitInitialInterval = 1 * time.Second
+	defaultStateWaitMaxInterval     = 10 * time.Second
+)
+
+// defaultStateWaitBeforeDelete bounds how long a delete lets a transitional
+// state settle, so an instance stuck in Unknown can still be deleted
+const defaultStateWaitBeforeDelete = 1 * time.Minute
+
+// ErrStateWaitTimeout is returned when an instance does not reach a stable
+// state before the operation deadline
+var ErrStateWaitTimeout = errors.New("timed out waiting for a stable instance state")
+
+// ErrRunningWaitTimeout is returned when an instance does not come up with
+// an IPv4 address before the operation deadline
+var ErrRunningWaitTimeout = errors.New("timed out waiting for the instance to be running")
+
+// defaultStateWaitPolicy returns the polling backoff of WaitForStableState.
+// Polling only stops at the context deadline, so MaxAttempts is unused.
+func defaultStateWaitPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: defaultStateWaitInitialInterval,
+		MaxBackoff:     defaultStateWaitMaxInterval,
+	}
+}
+
+// WaitForStableState returns the instance once it is Running, Stopped,
+// Suspended or Deleted, polling with backoff while multipass reports a
+// transitional state such as Starting or Delayed Shutdown. When ctx expires
+// first, the last instance seen is returned along with ErrStateWaitTimeout.
+func (c *MultipassClient) WaitForStableState(ctx context.Context, name string) (*common.MultipassInstance, error) {
+	instance, err := c.GetInstance(ctx, name)
+	if err != nil || instance.State.IsStable() {
+		return instance, err
+	}
+
+	return c.pollInstance(ctx, name, instance, "a stable state", ErrStateWaitTimeout, func(instance *common.MultipassInstance) bool {
+		return instance.State.IsStable()
+	})
+}
+
+// WaitForStableStateWithin waits like WaitForStableState, but for at most
+// wait. ErrStateWaitTimeout is returned with the last instance seen when wait
+// ends first, while ctx itself stays usable.
+func (c *MultipassClient) WaitForStableStateWithin(ctx context.Context, name string, wait time.Duration) (*common.MultipassInstance, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	return c.WaitForStableState(waitCtx, name)
+}
+
+// WaitBeforeDelete waits like WaitForStableState, but for at most the
+// delete wait of the client. When the instance does not settle in time, the
+// last instance seen is returned without an error so it can be deleted anyway.
+func (c *MultipassClient) WaitBeforeDelete(ctx context.Context, name string) (*common.MultipassInstance, error) {
+	instance, err := c.WaitForStableStateWithin(ctx, name, c.deleteWait)
+	if errors.Is(err, ErrStateWaitTimeout) && ctx.Err() == nil {
+		tflog.Warn(ctx, "deleting multipass instance in a transitional state", map[string]interface{}{
+			"name":  name,
+			"state": instance.State.String(),
+			"wait":  c.deleteWait.String(),
+		})
+		return instance, nil
+	}
+	return instance, err
+}
+
+// WaitForRunning returns the instance once it is Running with an IPv4
+// address, e.g. after a restart. When ctx expires first, the last instance
+// seen is returned along with ErrRunningWaitTimeout.
+func (c *MultipassClient) WaitForRunning(ctx context.Context, name string) (*common.MultipassInstance, error) {
+	running := func(instance *common.MultipassInstance) bool {
+		return instance.State == common.StateRunning && len(instance.IPv4) > 0
+	}
+
+	// A restart only just invalidated the cache, so read the instance directly
+	instance, err := c.getInstance(ctx, name)
+	if err != nil || running(instance) {
+		return instance, err
+	}
+
+	return c.pollInstance(ctx, name, instance, "Running with an IPv4 address", ErrRunningWaitTimeout, running)
+}
+
+// pollInstance polls an instance with backoff until ready reports true,
+// returning timeoutErr when ctx expires first
+func (c *MultipassClient) pollInstance(ctx context.Context, name string, instance *common.MultipassInstance, target string, timeoutErr error, ready func(*common.MultipassInstance) bool) (*common.MultipassInstance, error) {
+	// The batched snapshot holds the transitional state, so poll the
+	// instance directly and make later reads fetch fresh data
+	c.invalidateReadCache()
+	defer c.invalidateReadCache()
+
+	for poll := 1; ; poll++ {
+		delay := c.stateWait.backoff(poll)
+		tflog.Debug(ctx, "waiting for multipass instance to reach "+target, map[string]interface{}{
+			"name":  name,
+			"state": instance.State.String(),
+			"poll":  poll,
+			"delay": delay.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return instance, fmt.Errorf("%w: instance %s is still %s", timeoutErr, name, instance.State)
+		case <-time.After(delay):
+		}
+
+		current, err := c.getInstance(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return instance, fmt.Errorf("%w: instance %s is still %s", timeoutErr, name, instance.State)
+			}
+			return nil, err
+		}
+
+		instance = current
+		if ready(instance) {
+			return instance, nil
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// writeStateBinary creates a fake multipass binary that reports the given
+// states for instance "web", one per info call, repeating the last one
+func writeStateBinary(t *testing.T, states ...common.InstanceState) (string, string) {
+	t.Helper()
+
+	body := `count=$(grep -c '^info' "$calls")
+`
+	for i, state := range states {
+		if i < len(states)-1 {
+			body += fmt.Sprintf(`if [ "$count" -eq %d ]; then state='%s'; fi
+`, i+1, state)
+		}
+	}
+	body += fmt.Sprintf(`if [ "$count" -ge %d ]; then state='%s'; fi
+echo "{\"errors\": [], \"info\": {\"web\": {\"state\": \"$state\"}}}"
+`, len(states), states[len(states)-1])
+
+	return writeFakeMultipass(t, body)
+}
+
+func newStateWaitClient(binary string) *MultipassClient {
+	client := NewMultipassClient(binary)
+	client.stateWait = RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	return client
+}
+
+func TestWaitForStableState(t *testing.T) {
+	binary, calls := writeStateBinary(t, common.StateStarting, common.StateUnknown, common.StateRunning)
+	client := newStateWaitClient(binary)
+
+	instance, err := client.WaitForStableState(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("WaitForStableState() error = %v", err)
+	}
+	if instance.State != common.StateRunning {
+		t.Errorf("Expected state Running, got %s", instance.State)
+	}
+	if got := len(readCalls(t, calls)); got != 3 {
+		t.Errorf("Expected 3 info calls, got %d", got)
+	}
+}
+
+func TestWaitForStableStateAlreadyStable(t *testing.T) {
+	binary, calls := writeStateBinary(t, common.StateStopped)
+	client := newStateWaitClient(binary)
+
+	instance, err := client.WaitForStableState(context.Background(), "web")
+	if err != nil || instance.State != common.StateStopped {
+		t.Fatalf("WaitForStableState() = %v, %v, want Stopped", instance, err)
+	}
+	if got := len(readCalls(t, calls)); got != 1 {
+		t.Errorf("Expected a single info call, got %d", got)
+	}
+}
+
+func TestWaitForStableStateTimeout(t *testing.T) {
+	binary, _ := writeStateBinary(t, common.StateDelayedShutdown)
+	client := newStateWaitClient(binary)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	instance, err := client.WaitForStableState(ctx, "web")
+	if !errors.Is(err, ErrStateWaitTimeout) {
+		t.Fatalf("Expected ErrStateWaitTimeout, got %v", err)
+	}
+	if instance == nil || instance.State != common.StateDelayedShutdown {
+		t.Errorf("Expected the last seen instance in state Delayed Shutdown, got %v", instance)
+	}
+}
+
+func TestWaitForStableStateWithin(t *testing.T) {
+	binary, _ := writeStateBinary(t, common.StateStarting)
+	client := newStateWaitClient(binary)
+
+	// The wait ends on its own, without a deadline on the context
+	instance, err := client.WaitForStableStateWithin(context.Background(), "web", 20*time.Millisecond)
+	if !errors.Is(err, ErrStateWaitTimeout) {
+		t.Fatalf("Expected ErrStateWaitTimeout, got %v", err)
+	}
+	if instance == nil || instance.State != common.StateStarting {
+		t.Errorf("Expected the last seen instance in state Starting, got %v", instance)
+	}
+}
+
+func TestWaitBeforeDelete(t *testing.T) {
+	binary, calls := writeStateBinary(t, common.StateUnknown)
+	client := newStateWaitClient(binary)
+	client.deleteWait = 20 * time.Millisecond
+
+	// A stuck instance is handed back for deletion once the delete wait ends
+	instance, err := client.WaitBeforeDelete(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("WaitBeforeDelete() error = %v", err)
+	}
+	if instance.State != common.StateUnknown {
+		t.Errorf("Expected the last seen instance in state Unknown, got %s", instance.State)
+	}
+	if got := len(readCalls(t, calls)); got < 2 {
+		t.Errorf("Expected the instance to be polled, got %d info calls", got)
+	}
+}
+
+func TestInstanceStateIsStable(t *testing.T) {
+	stable := []common.InstanceState{common.StateRunning, common.StateStopped, common.StateSuspended, common.StateDeleted}
+	for _, state := range stable {
+		if !state.IsStable() {
+			t.Errorf("Expected %s to be stable", state)
+		}
+	}
+
+	transitional := []common.InstanceState{common.StateStarting, common.StateRestarting, common.StateDelayedShutdown, common.StateSuspending, common.StateUnknown, "Frobnicating"}
+	for _, state := range transitional {
+		if state.IsStable() {
+			t.Errorf("Expected %s not to be stable", state)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &ValidInstanceNameFunction{}
+
+func NewValidInstanceNameFunction() function.Function {
+	return &ValidInstanceNameFunction{}
+}
+
+// ValidInstanceNameFunction reports whether a string is a valid instance name.
+type ValidInstanceNameFunction struct{}
+
+func (f *ValidInstanceNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "valid_instance_name"
+}
+
+func (f *ValidInstanceNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Check whether a string is a valid instance name",
+		MarkdownDescription: "Returns true when the name is accepted by multipass: it starts with a letter, contains only letters, digits and hyphens, does not end with a hyphen and is at most 63 characters long.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Instance name to check",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ValidInstanceNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, validateInstanceName(name) == nil))
+}
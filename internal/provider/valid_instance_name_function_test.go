@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestValidInstanceNameFunction(t *testing.T) {
+	testCases := map[string]bool{
+		"web-1":     true,
+		"1web":      false,
+		"web_1":     false,
+		"web-":      false,
+		"":          false,
+		"Primary01": true,
+	}
+
+	for name, want := range testCases {
+		got, funcErr := runFunction(t, NewValidInstanceNameFunction(), types.StringValue(name))
+		if funcErr != nil {
+			t.Fatalf("valid_instance_name(%q) error = %s", name, funcErr)
+		}
+		if !got.Equal(types.BoolValue(want)) {
+			t.Errorf("valid_instance_name(%q) = %s, want %v", name, got, want)
+		}
+	}
+}
+
+func TestAccValidInstanceNameFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(testAccFunctionsTerraformVersion),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::multipass::valid_instance_name("web_1")
+}
+`,
+				Check: resource.TestCheckOutput("test", "false"),
+			},
+		},
+	})
+}
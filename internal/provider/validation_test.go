@@ -1,11 +1,15 @@
 package provider
 
 import (
-	"fmt"
-	"regexp"
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/sh05/terraform-provider-multipass/internal/common"
 )
 
@@ -28,9 +32,10 @@ func TestValidateInstanceName(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Valid name with underscores",
+			name:    "Name with underscores",
 			input:   "test_instance_name",
-			wantErr: false,
+			wantErr: true,
+			errMsg:  "invalid characters",
 		},
 		{
 			name:    "Empty name",
@@ -70,7 +75,7 @@ func TestValidateInstanceName(t *testing.T) {
 		},
 		{
 			name:    "Name too long",
-			input:   strings.Repeat("a", 256),
+			input:   strings.Repeat("a", 64),
 			wantErr: true,
 			errMsg:  "name too long",
 		},
@@ -88,8 +93,12 @@ func TestValidateInstanceName(t *testing.T) {
 		{
 			name:    "Name with consecutive dashes",
 			input:   "test--instance",
-			wantErr: true,
-			errMsg:  "consecutive dashes not allowed",
+			wantErr: false,
+		},
+		{
+			name:    "Maximum length name",
+			input:   strings.Repeat("a", 63),
+			wantErr: false,
 		},
 	}
 
@@ -214,8 +223,13 @@ func TestValidateMemoryValue(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Memory too small",
+			name:    "Valid minimum memory",
 			input:   "128M",
+			wantErr: false,
+		},
+		{
+			name:    "Memory too small",
+			input:   "64M",
 			wantErr: true,
 			errMsg:  "minimum memory",
 		},
@@ -305,8 +319,13 @@ func TestValidateDiskValue(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Disk too small",
+			name:    "Valid small disk",
 			input:   "1G",
+			wantErr: false,
+		},
+		{
+			name:    "Disk too small",
+			input:   "256M",
 			wantErr: true,
 			errMsg:  "minimum disk",
 		},
@@ -577,7 +596,7 @@ func TestValidateLaunchOptions(t *testing.T) {
 				Image:  "22.04",
 				CPU:    "2",
 				Memory: "2G",
-				Disk:   "1G",
+				Disk:   "256M",
 			},
 			wantErr: true,
 			errMsg:  "invalid disk",
@@ -628,91 +647,123 @@ func TestValidateLaunchOptions(t *testing.T) {
 	}
 }
 
-// Mock validation functions - these would typically be implemented in the actual provider code
-
-func validateInstanceName(name string) error {
-	if name == "" {
-		return fmt.Errorf("name cannot be empty")
+// TestCloudInitFileValidator tests that the schema validator requires an existing file
+func TestCloudInitFileValidator(t *testing.T) {
+	tempDir := t.TempDir()
+	existing := filepath.Join(tempDir, "cloud-init.yaml")
+	if err := os.WriteFile(existing, []byte("#cloud-config\n"), 0644); err != nil {
+		t.Fatalf("Failed to create cloud-init file: %v", err)
 	}
-	if len(name) > 255 {
-		return fmt.Errorf("name too long")
+	directory := filepath.Join(tempDir, "configs.yaml")
+	if err := os.Mkdir(directory, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
 	}
-	if matched, _ := regexp.MatchString(`[^a-zA-Z0-9\-_]`, name); matched {
-		return fmt.Errorf("invalid characters in name")
-	}
-	if matched, _ := regexp.MatchString(`^[0-9\-]`, name); matched {
-		return fmt.Errorf("name cannot start with number or dash")
-	}
-	if strings.HasSuffix(name, "-") {
-		return fmt.Errorf("name cannot end with dash")
-	}
-	if strings.Contains(name, "--") {
-		return fmt.Errorf("consecutive dashes not allowed")
+
+	testCases := []struct {
+		name    string
+		value   types.String
+		wantErr string
+	}{
+		{"Existing file", types.StringValue(existing), ""},
+		{"Missing file", types.StringValue(filepath.Join(tempDir, "missing.yaml")), "cloud-init file not found"},
+		{"Directory", types.StringValue(directory), "is a directory"},
+		{"Wrong extension", types.StringValue(filepath.Join(tempDir, "cloud-init.txt")), "must be a YAML file"},
+		{"Null value", types.StringNull(), ""},
+		{"Unknown value", types.StringUnknown(), ""},
 	}
-	return nil
-}
 
-func validateCPUValue(cpu string) error {
-	if cpu == "" {
-		return fmt.Errorf("CPU value cannot be empty")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("cloud_init"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+
+			cloudInitFileValidator().ValidateString(context.Background(), req, resp)
+
+			if tc.wantErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Unexpected error: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("Expected error, got none")
+			}
+			if detail := resp.Diagnostics[0].Detail(); !strings.Contains(detail, tc.wantErr) {
+				t.Errorf("Expected error to contain %q, got: %s", tc.wantErr, detail)
+			}
+		})
 	}
-	// Add validation logic here
-	return nil
 }
 
-func validateMemoryValue(memory string) error {
-	if memory == "" {
-		return fmt.Errorf("memory value cannot be empty")
+// TestImageValidator tests image validation, which leaves file:// images
+// to be read at plan time
+func TestImageValidator(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testCases := []struct {
+		name    string
+		value   types.String
+		wantErr string
+	}{
+		{"Catalog image", types.StringValue("22.04"), ""},
+		{"HTTPS URL", types.StringValue("https://example.com/images/custom.img"), ""},
+		{"Missing file", types.StringValue("file://" + filepath.Join(tempDir, "missing.img")), ""},
+		{"Relative file", types.StringValue("file://custom.img"), "must be absolute"},
+		{"HTTP URL", types.StringValue("http://example.com/custom.img"), "unsupported image scheme"},
+		{"URL without host", types.StringValue("https:///custom.img"), "invalid image URL"},
+		{"Null value", types.StringNull(), ""},
 	}
-	// Add validation logic here
-	return nil
-}
 
-func validateDiskValue(disk string) error {
-	if disk == "" {
-		return fmt.Errorf("disk value cannot be empty")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("image"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+
+			imageValidator().ValidateString(context.Background(), req, resp)
+
+			if tc.wantErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Unexpected error: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("Expected error, got none")
+			}
+			if detail := resp.Diagnostics[0].Detail(); !strings.Contains(detail, tc.wantErr) {
+				t.Errorf("Expected error to contain %q, got: %s", tc.wantErr, detail)
+			}
+		})
 	}
-	// Add validation logic here
-	return nil
 }
 
-func validateCloudInitFile(path string) error {
-	if path == "" {
-		return nil // Optional field
+// TestValidateImageChecksum tests image checksum validation
+func TestValidateImageChecksum(t *testing.T) {
+	digest := strings.Repeat("ab12", 16)
+
+	for _, valid := range []string{"", digest, "sha256:" + digest, strings.ToUpper(digest)} {
+		if err := validateImageChecksum(valid); err != nil {
+			t.Errorf("validateImageChecksum(%q) unexpected error: %v", valid, err)
+		}
 	}
-	if len(path) > 1000 {
-		return fmt.Errorf("path too long")
+	for _, invalid := range []string{"abc", "md5:" + digest, digest + "00", "sha256:" + strings.Repeat("zz", 32)} {
+		if err := validateImageChecksum(invalid); err == nil {
+			t.Errorf("validateImageChecksum(%q) expected an error", invalid)
+		}
 	}
-	// Add more validation logic here
-	return nil
 }
 
-func validateTimeoutValue(timeout string) error {
-	if timeout == "" {
-		return nil // Optional field
-	}
-	// Add validation logic here
-	return nil
-}
+// TestCPUValidatorMessage tests the diagnostic reported for invalid CPU values
+func TestCPUValidatorMessage(t *testing.T) {
+	req := validator.StringRequest{Path: path.Root("cpu"), ConfigValue: types.StringValue("abc")}
+	resp := &validator.StringResponse{}
 
-func validateLaunchOptions(opts *common.LaunchOptions) error {
-	if err := validateInstanceName(opts.Name); err != nil {
-		return fmt.Errorf("invalid name: %w", err)
-	}
-	if err := validateCPUValue(opts.CPU); err != nil {
-		return fmt.Errorf("invalid CPU: %w", err)
-	}
-	if err := validateMemoryValue(opts.Memory); err != nil {
-		return fmt.Errorf("invalid memory: %w", err)
-	}
-	if err := validateDiskValue(opts.Disk); err != nil {
-		return fmt.Errorf("invalid disk: %w", err)
-	}
-	if err := validateCloudInitFile(opts.CloudInit); err != nil {
-		return fmt.Errorf("invalid cloud-init: %w", err)
+	cpuValidator().ValidateString(context.Background(), req, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected error, got none")
 	}
-	if err := validateTimeoutValue(opts.Timeout); err != nil {
-		return fmt.Errorf("invalid timeout: %w", err)
+	if detail := resp.Diagnostics[0].Detail(); !strings.Contains(detail, "invalid CPU value") {
+		t.Errorf("Expected error to mention the invalid CPU value, got: %s", detail)
 	}
-	return nil
-}
\ No newline at end of file
+}
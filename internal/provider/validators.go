@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/sh05/terraform-provider-multipass/internal/common"
+)
+
+// Limits enforced on instance settings before anything is launched
+const (
+	maxInstanceNameLength = 63
+	maxCPUCount           = 1024
+	minMemoryBytes        = 128 << 20
+	maxMemoryBytes        = 512 << 30
+	minDiskBytes          = 512 << 20
+	maxDiskBytes          = 8 << 40
+	maxTimeout            = 24 * time.Hour
+	maxCloudInitPathLen   = 1000
+)
+
+// instanceNamePattern is the character set multipass accepts in instance names
+var instanceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// validateInstanceName checks a name against the rules multipass enforces:
+// letters, digits and hyphens, starting with a letter and not ending with a hyphen
+func validateInstanceName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if len(name) > maxInstanceNameLength {
+		return fmt.Errorf("name too long, maximum is %d characters", maxInstanceNameLength)
+	}
+	if strings.Contains(name, " ") {
+		return fmt.Errorf("spaces not allowed in name")
+	}
+	if !instanceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid characters in name, only letters, digits and hyphens are allowed")
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		return fmt.Errorf("name cannot start with number")
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("name cannot start with dash")
+	}
+	if strings.HasSuffix(name, "-") {
+		return fmt.Errorf("name cannot end with dash")
+	}
+	return nil
+}
+
+// aliasNamePattern is the character set multipass accepts in alias names
+var aliasNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// validateAliasName checks that an alias name can be used as a command name
+// on the host
+func validateAliasName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid characters in name, only letters, digits, dots, underscores and hyphens are allowed, starting with a letter or digit")
+	}
+	return nil
+}
+
+// validateCPUValue checks that cpu is a positive whole number of CPUs
+func validateCPUValue(cpu string) error {
+	if cpu == "" {
+		return fmt.Errorf("CPU value cannot be empty")
+	}
+
+	count, err := strconv.Atoi(cpu)
+	if err != nil {
+		if _, floatErr := strconv.ParseFloat(cpu, 64); floatErr == nil {
+			return fmt.Errorf("CPU value must be an integer")
+		}
+		return fmt.Errorf("CPU value must be a number")
+	}
+
+	if count < 1 {
+		return fmt.Errorf("CPU value must be greater than 0")
+	}
+	if count > maxCPUCount {
+		return fmt.Errorf("CPU value exceeds maximum of %d", maxCPUCount)
+	}
+	return nil
+}
+
+// validateSizeValue checks a memory or disk size against the given bounds
+func validateSizeValue(kind string, size string, minimum int64, maximum int64) error {
+	if size == "" {
+		return fmt.Errorf("%s value cannot be empty", kind)
+	}
+
+	bytes, err := parseSize(size)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(size, ".") {
+		return fmt.Errorf("decimal values not supported, use a smaller unit instead")
+	}
+	if bytes == 0 {
+		return fmt.Errorf("%s value must be greater than 0", kind)
+	}
+	if bytes < minimum {
+		return fmt.Errorf("minimum %s size is %dM", kind, minimum>>20)
+	}
+	if bytes > maximum {
+		return fmt.Errorf("%s value exceeds maximum of %dG", kind, maximum>>30)
+	}
+	return nil
+}
+
+// validateMemoryValue checks a memory size such as "2G"
+func validateMemoryValue(memory string) error {
+	return validateSizeValue("memory", memory, minMemoryBytes, maxMemoryBytes)
+}
+
+// validateDiskValue checks a disk size such as "10G"
+func validateDiskValue(disk string) error {
+	return validateSizeValue("disk", disk, minDiskBytes, maxDiskBytes)
+}
+
+// validateCloudInitFile checks the form of a cloud-init file path. An empty
+// path is allowed since cloud-init is optional.
+func validateCloudInitFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	if len(path) > maxCloudInitPathLen {
+		return fmt.Errorf("path too long, maximum is %d characters", maxCloudInitPathLen)
+	}
+	if strings.ContainsRune(path, '\x00') {
+		return fmt.Errorf("path contains invalid characters")
+	}
+
+	lower := strings.ToLower(path)
+	if !strings.HasSuffix(lower, ".yaml") && !strings.HasSuffix(lower, ".yml") {
+		return fmt.Errorf("cloud-init file must be a YAML file, it must have .yaml or .yml extension")
+	}
+	return nil
+}
+
+// Prefixes of the image URLs multipass can launch from
+const (
+	imageFilePrefix  = "file://"
+	imageHTTPSPrefix = "https://"
+)
+
+// imageChecksumPattern matches a sha256 digest, optionally prefixed with the algorithm
+var imageChecksumPattern = regexp.MustCompile(`^(sha256:)?[0-9a-fA-F]{64}$`)
+
+// isImageURL reports whether an image is a file:// or https:// URL rather
+// than a name from the multipass catalog
+func isImageURL(image string) bool {
+	return strings.HasPrefix(image, imageFilePrefix) || strings.HasPrefix(image, imageHTTPSPrefix)
+}
+
+// validateImageValue checks the form of an image. Catalog names such as
+// "22.04" are left to multipass, while URLs must be absolute file:// paths
+// or https:// URLs.
+func validateImageValue(image string) error {
+	scheme, rest, ok := strings.Cut(image, "://")
+	if !ok {
+		return nil
+	}
+
+	switch scheme {
+	case "file":
+		if !strings.HasPrefix(rest, "/") {
+			return fmt.Errorf("file image path must be absolute, e.g. file:///home/ubuntu/custom.img")
+		}
+	case "https":
+		parsed, err := url.Parse(image)
+		if err != nil || parsed.Host == "" {
+			return fmt.Errorf("invalid image URL, expected e.g. https://example.com/custom.img")
+		}
+	default:
+		return fmt.Errorf("unsupported image scheme %q, only file:// and https:// images are supported", scheme)
+	}
+	return nil
+}
+
+// validateImageChecksum checks a sha256 digest such as "sha256:<hex>"
+func validateImageChecksum(checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	if !imageChecksumPattern.MatchString(checksum) {
+		return fmt.Errorf("checksum must be a sha256 digest of 64 hexadecimal characters, optionally prefixed with sha256:")
+	}
+	return nil
+}
+
+// validateTimeoutValue checks a duration such as "5m" or "1h30m". An empty
+// value is allowed and means the default timeout.
+func validateTimeoutValue(timeout string) error {
+	if timeout == "" {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(timeout, 64); err == nil {
+		return fmt.Errorf("timeout must include time unit, e.g. %ss", timeout)
+	}
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected a value such as 30s, 5m or 1h30m", timeout)
+	}
+
+	if duration < 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if duration == 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	if duration > maxTimeout {
+		return fmt.Errorf("timeout exceeds maximum of %s", maxTimeout)
+	}
+	return nil
+}
+
+// validateLaunchOptions checks launch options before multipass is invoked.
+// Empty CPU, memory and disk values are left to the multipass defaults.
+func validateLaunchOptions(opts *common.LaunchOptions) error {
+	if err := validateInstanceName(opts.Name); err != nil {
+		return fmt.Errorf("invalid name: %w", err)
+	}
+	if opts.CPU != "" {
+		if err := validateCPUValue(opts.CPU); err != nil {
+			return fmt.Errorf("invalid CPU: %w", err)
+		}
+	}
+	if opts.Memory != "" {
+		if err := validateMemoryValue(opts.Memory); err != nil {
+			return fmt.Errorf("invalid memory: %w", err)
+		}
+	}
+	if opts.Disk != "" {
+		if err := validateDiskValue(opts.Disk); err != nil {
+			return fmt.Errorf("invalid disk: %w", err)
+		}
+	}
+	if err := validateImageValue(opts.Image); err != nil {
+		return fmt.Errorf("invalid image: %w", err)
+	}
+	if opts.Blueprint != "" && (opts.Image != "" || opts.Remote != "") {
+		return fmt.Errorf("invalid blueprint: a blueprint cannot be combined with an image or image remote")
+	}
+	if opts.Remote != "" && (opts.Image == "" || isImageURL(opts.Image)) {
+		return fmt.Errorf("invalid image remote: a remote needs an image name such as 24.04")
+	}
+	if err := validateCloudInitFile(opts.CloudInit); err != nil {
+		return fmt.Errorf("invalid cloud-init: %w", err)
+	}
+	if opts.CloudInit != "" && opts.CloudInitData != "" {
+		return fmt.Errorf("invalid cloud-init: a cloud-init file cannot be combined with cloud-init data")
+	}
+	if err := validateTimeoutValue(opts.Timeout); err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+	return nil
+}
+
+// Ensure the validators satisfy the framework interface.
+var _ validator.String = stringValueValidator{}
+
+// stringValueValidator adapts a validate function to a schema validator
+type stringValueValidator struct {
+	label    string
+	validate func(string) error
+}
+
+func (v stringValueValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be a valid %s", v.label)
+}
+
+func (v stringValueValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringValueValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if err := v.validate(value); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+			fmt.Sprintf("invalid %s %q: %s", v.label, value, err))
+	}
+}
+
+// instanceNameValidator validates instance names at plan time
+func instanceNameValidator() validator.String {
+	return stringValueValidator{label: "instance name", validate: validateInstanceName}
+}
+
+// aliasNameValidator validates alias names at plan time
+func aliasNameValidator() validator.String {
+	return stringValueValidator{label: "alias name", validate: validateAliasName}
+}
+
+// cpuValidator validates CPU counts at plan time
+func cpuValidator() validator.String {
+	return stringValueValidator{label: "CPU value", validate: validateCPUValue}
+}
+
+// memoryValidator validates memory sizes at plan time
+func memoryValidator() validator.String {
+	return stringValueValidator{label: "memory value", validate: validateMemoryValue}
+}
+
+// diskValidator validates disk sizes at plan time
+func diskValidator() validator.String {
+	return stringValueValidator{label: "disk value", validate: validateDiskValue}
+}
+
+// cloudInitFileValidator validates the cloud-init path and that the file exists
+func cloudInitFileValidator() validator.String {
+	return stringValueValidator{label: "cloud-init file", validate: func(path string) error {
+		if err := validateCloudInitFile(path); err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("cloud-init file not found")
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read cloud-init file: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("cloud-init path is a directory, not a file")
+		}
+		return nil
+	}}
+}
+
+// imageValidator validates the image form. A file:// image is only read
+// when an instance is planned from it, since existing instances must not
+// depend on the file still being there.
+func imageValidator() validator.String {
+	return stringValueValidator{label: "image", validate: validateImageValue}
+}
+
+// imageChecksumValidator validates image checksums at plan time
+func imageChecksumValidator() validator.String {
+	return stringValueValidator{label: "image checksum", validate: validateImageChecksum}
+}
+
+// oneOfValidator validates that a value is one of the allowed values
+func oneOfValidator(label string, allowed ...string) validator.String {
+	return stringValueValidator{label: label, validate: func(value string) error {
+		for _, candidate := range allowed {
+			if value == candidate {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}}
+}
+
+// regexValidator validates that a value is a valid regular expression
+func regexValidator() validator.String {
+	return stringValueValidator{label: "regular expression", validate: func(value string) error {
+		_, err := regexp.Compile(value)
+		return err
+	}}
+}
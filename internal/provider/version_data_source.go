@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VersionDataSource{}
+
+func NewVersionDataSource() datasource.DataSource {
+	return &VersionDataSource{}
+}
+
+// VersionDataSource defines the data source implementation.
+type VersionDataSource struct {
+	client *MultipassClient
+}
+
+// VersionDataSourceModel describes the data source data model.
+type VersionDataSourceModel struct {
+	Id            types.String `tfsdk:"id"`
+	ClientVersion types.String `tfsdk:"client_version"`
+	DaemonVersion types.String `tfsdk:"daemon_version"`
+	Capabilities  types.Map    `tfsdk:"capabilities"`
+}
+
+func (d *VersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_version"
+}
+
+func (d *VersionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Multipass version data source. Reports the installed multipass client and daemon versions and which version dependent features they support.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier",
+				Computed:            true,
+			},
+			"client_version": schema.StringAttribute{
+				MarkdownDescription: "Version of the multipass client",
+				Computed:            true,
+			},
+			"daemon_version": schema.StringAttribute{
+				MarkdownDescription: "Version of the multipass daemon (multipassd). Empty when the daemon is not reachable.",
+				Computed:            true,
+			},
+			"capabilities": schema.MapAttribute{
+				MarkdownDescription: "Version dependent features (e.g. `snapshots`, `clone`) mapped to whether the installed multipass supports them",
+				Computed:            true,
+				ElementType:         types.BoolType,
+			},
+		},
+	}
+}
+
+func (d *VersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*MultipassClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *MultipassClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *VersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VersionDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "reading multipass version")
+
+	version, err := d.client.DetectVersion(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read multipass version, got error: %s", err))
+		return
+	}
+
+	capabilityValues := make(map[string]attr.Value, len(capabilityMinVersions))
+	for capability := range capabilityMinVersions {
+		capabilityValues[string(capability)] = types.BoolValue(d.client.Supports(capability))
+	}
+
+	data.Id = types.StringValue(version.Client)
+	data.ClientVersion = types.StringValue(version.Client)
+	data.DaemonVersion = types.StringValue(version.Daemon)
+	data.Capabilities = types.MapValueMust(types.BoolType, capabilityValues)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVersionDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVersionDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.multipass_version.test", "id"),
+					resource.TestCheckResourceAttrSet("data.multipass_version.test", "client_version"),
+					resource.TestCheckResourceAttrSet("data.multipass_version.test", "capabilities.snapshots"),
+				),
+			},
+		},
+	})
+}
+
+const testAccVersionDataSourceConfig = `
+data "multipass_version" "test" {}
+`